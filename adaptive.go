@@ -0,0 +1,102 @@
+package multicall
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// defaultMinAdaptiveChunkSize is the smallest chunk size of CallAdaptive unless another
+// size is set with WithAdaptiveChunkSizes.
+const defaultMinAdaptiveChunkSize = 10
+
+// CallAdaptive makes multiple multicalls by chunking given calls like CallChunked but
+// tunes the chunk size to keep the latency of each chunk near targetLatency. It starts
+// from the minimum chunk size and grows at most twice the size after each chunk. After
+// a failed chunk, the chunk size is quartered and the chunk is sent again until the
+// chunk size is at the minimum. The chunk sizes are set with WithAdaptiveChunkSizes and
+// the maximum is the default chunk size unless set.
+func (caller *Caller) CallAdaptive(opts *bind.CallOpts, targetLatency time.Duration, calls ...*Call) ([]*Call, error) {
+	minSize, maxSize := caller.adaptiveChunkSizes()
+	ctx, _ := caller.callContext(opts)
+	log := caller.log()
+
+	var multiErr MultiError
+	size := minSize
+	for i, offset := 0, 0; offset < len(calls); i++ {
+		if err := caller.wait(ctx); err != nil {
+			return calls[:offset], err
+		}
+
+		end := offset + size
+		if end > len(calls) {
+			end = len(calls)
+		}
+		traceOpts, endTrace := caller.traceChunk(opts, i, calls[offset:end])
+		chunkOpts, cancel := caller.withChunkTimeout(traceOpts)
+		start := time.Now()
+		_, err := caller.Call(chunkOpts, calls[offset:end]...)
+		elapsed := time.Since(start)
+		cancel()
+		endTrace(err)
+		log.Debugf("multicall: chunk [%d] with %d calls took %s", i, end-offset, elapsed)
+		caller.chunkDone(i, end-offset, start, err)
+
+		if chunkErr := (*MultiError)(nil); errors.As(err, &chunkErr) {
+			multiErr.merge(offset, chunkErr)
+		} else if err != nil {
+			if size == minSize {
+				return calls[:offset], newChunkError(i, offset, end-offset, err)
+			}
+			size = clampChunkSize(size/4, minSize, maxSize)
+			continue
+		}
+
+		offset = end
+		caller.reportProgress(offset, len(calls))
+		size = clampChunkSize(nextChunkSize(size, elapsed, targetLatency), minSize, maxSize)
+	}
+	return calls, multiErr.errOrNil()
+}
+
+// nextChunkSize scales the chunk size by the ratio of the target latency to the latency
+// of the chunk, growing at most twice the size.
+func nextChunkSize(size int, elapsed, targetLatency time.Duration) int {
+	if elapsed <= 0 {
+		return size * 2
+	}
+	next := int(float64(size) * float64(targetLatency) / float64(elapsed))
+	if next > size*2 {
+		return size * 2
+	}
+	return next
+}
+
+func clampChunkSize(size, minSize, maxSize int) int {
+	if size < minSize {
+		return minSize
+	}
+	if size > maxSize {
+		return maxSize
+	}
+	return size
+}
+
+// adaptiveChunkSizes returns the minimum and maximum chunk sizes of CallAdaptive.
+func (caller *Caller) adaptiveChunkSizes() (minSize, maxSize int) {
+	minSize, maxSize = caller.minChunkSize, caller.maxChunkSize
+	if minSize <= 0 {
+		minSize = defaultMinAdaptiveChunkSize
+	}
+	if maxSize <= 0 {
+		maxSize = caller.chunkSize
+	}
+	if maxSize <= 0 {
+		maxSize = defaultChunkSize
+	}
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+	return
+}
@@ -0,0 +1,79 @@
+package multicall
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_CallAdaptive(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 100; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", i%2 == 0))
+	}
+
+	// the provider fails the chunks larger than 16 calls
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		if len(calls) > 16 {
+			return errors.New("response too large")
+		}
+		return nil
+	}
+	hook := &testChunkHook{}
+	caller := &Caller{contract: stub, chunkHook: hook, minChunkSize: 2, maxChunkSize: 64}
+
+	calls, err = caller.CallAdaptive(nil, time.Hour, calls...)
+	r.NoError(err)
+	for i, call := range calls {
+		r.Equal(i%2 == 0, call.Outputs.(*boolOutput).Val1)
+	}
+
+	r.Equal(2, hook.chunks[0].size)
+	r.Equal(4, hook.chunks[1].size)
+	var failed int
+	for _, chunk := range hook.chunks {
+		r.LessOrEqual(chunk.size, 64)
+		if chunk.err != nil {
+			failed++
+			r.Greater(chunk.size, 16)
+		}
+	}
+	r.Greater(failed, 0)
+
+	// failing at the minimum chunk size
+	caller.minChunkSize = 32
+	_, err = caller.CallAdaptive(nil, time.Hour, calls...)
+	r.ErrorContains(err, "response too large")
+
+	// only the completed chunks are returned
+	var chunkCount int
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		if chunkCount++; chunkCount == 2 {
+			return errors.New("rpc down")
+		}
+		return nil
+	}
+	caller.minChunkSize, caller.maxChunkSize = 16, 16
+	results, err := caller.CallAdaptive(nil, time.Hour, calls...)
+	r.ErrorContains(err, "rpc down")
+	r.Equal(calls[:16], results)
+}
+
+func TestNextChunkSize(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(20, nextChunkSize(10, time.Millisecond, time.Second))
+	r.Equal(5, nextChunkSize(10, 2*time.Second, time.Second))
+	r.Equal(20, nextChunkSize(10, 0, time.Second))
+	r.Equal(3, clampChunkSize(1, 3, 10))
+	r.Equal(10, clampChunkSize(20, 3, 10))
+}
@@ -0,0 +1,25 @@
+package multicall
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DeployerAddress is the sender of the presigned transaction which deploys the multicall
+// contract at DefaultAddress on any chain.
+// Taken from https://github.com/mds1/multicall
+const DeployerAddress = "0x05f32B3cC3888453ff71B01135B34FF8e41263F2"
+
+// DeterministicAddress returns the address of the multicall contract deployed with the
+// presigned transaction, which is the first contract created by DeployerAddress. It is
+// the same as DefaultAddress. Multicall3 is not deployed with CREATE2, see ComputeAddress
+// for the custom deployments which are.
+func DeterministicAddress() common.Address {
+	return crypto.CreateAddress(common.HexToAddress(DeployerAddress), 0)
+}
+
+// ComputeAddress returns the address of a contract deployed with CREATE2 by the deployer
+// with the salt and the hash of the init code.
+func ComputeAddress(deployer common.Address, salt [32]byte, initCodeHash []byte) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash)
+}
@@ -0,0 +1,29 @@
+package multicall
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministicAddress(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(common.HexToAddress(DefaultAddress), DeterministicAddress())
+}
+
+func TestComputeAddress(t *testing.T) {
+	r := require.New(t)
+
+	// from the examples of EIP-1014
+	r.Equal(
+		common.HexToAddress("0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"),
+		ComputeAddress(common.Address{}, [32]byte{}, crypto.Keccak256([]byte{0x00})),
+	)
+	r.Equal(
+		common.HexToAddress("0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"),
+		ComputeAddress(common.HexToAddress("0xdeadbeef00000000000000000000000000000000"), [32]byte{}, crypto.Keccak256([]byte{0x00})),
+	)
+}
@@ -0,0 +1,88 @@
+package multicall
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimitHints are the parts of the error messages which tell that the node or the
+// provider has rate limited the requests.
+var rateLimitHints = []string{
+	"429",
+	"too many requests",
+	"rate limit",
+	"rate-limit",
+	"request limit",
+	"compute units",
+	"capacity",
+}
+
+// IsRateLimitError tells if the error looks like the node or the provider has rate
+// limited the requests. It is the default classifier of WithAdaptiveCooldown.
+func IsRateLimitError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, hint := range rateLimitHints {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// backpressure is the extra cooldown added between chunks after rate limit errors. The
+// extra cooldown is multiplied by the factor after each rate limit error and decreased by
+// the step after each chunk which succeeds.
+type backpressure struct {
+	step          time.Duration
+	factor        float64
+	maxCooldown   time.Duration
+	isRateLimited func(err error) bool
+
+	mu    sync.Mutex
+	extra time.Duration
+}
+
+// observe updates the extra cooldown with the outcome of a request.
+func (bp *backpressure) observe(err error) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	switch {
+	case err == nil:
+		bp.extra -= bp.step
+		if bp.extra < 0 {
+			bp.extra = 0
+		}
+	case bp.isRateLimited(err):
+		bp.extra = time.Duration(float64(bp.extra) * bp.factor)
+		if bp.extra < bp.step {
+			bp.extra = bp.step
+		}
+		if bp.maxCooldown > 0 && bp.extra > bp.maxCooldown {
+			bp.extra = bp.maxCooldown
+		}
+	}
+}
+
+func (bp *backpressure) current() time.Duration {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	return bp.extra
+}
+
+// observeRateLimit reports the outcome of a request to the backpressure of the caller, if
+// there is any.
+func (caller *Caller) observeRateLimit(err error) {
+	if caller.backpressure != nil {
+		caller.backpressure.observe(err)
+	}
+}
+
+// cooldownFor returns the cooldown between chunks including the extra cooldown after rate
+// limit errors.
+func (caller *Caller) cooldownFor(cooldown time.Duration) time.Duration {
+	if caller.backpressure == nil {
+		return cooldown
+	}
+	return cooldown + caller.backpressure.current()
+}
@@ -0,0 +1,67 @@
+package multicall
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_AdaptiveCooldown(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	// the first two attempts are rate limited
+	var (
+		caller    *Caller
+		attempts  int
+		cooldowns []time.Duration
+	)
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		cooldowns = append(cooldowns, caller.cooldownFor(0))
+		attempts++
+		if attempts <= 2 {
+			return errors.New("429 Too Many Requests")
+		}
+		return nil
+	}
+	caller = NewWithContract(stub, WithAdaptiveCooldown(time.Millisecond, 4, 3*time.Millisecond, nil))
+
+	calls, err = caller.CallChunkedRetry(nil, 1, 0, 3, calls...)
+	r.NoError(err)
+	r.Len(calls, 3)
+	r.Equal([]time.Duration{0, time.Millisecond, 3 * time.Millisecond, 2 * time.Millisecond, time.Millisecond}, cooldowns)
+	r.Zero(caller.cooldownFor(0))
+
+	// other errors do not change the cooldown
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	_, err = caller.CallChunked(nil, 1, 0, calls...)
+	r.ErrorContains(err, "rpc down")
+	r.Equal(10*time.Millisecond, caller.cooldownFor(10*time.Millisecond))
+
+	// custom classifier
+	caller = NewWithContract(stub, WithAdaptiveCooldown(time.Millisecond, 2, 0, func(err error) bool { return true }))
+	_, err = caller.CallChunked(nil, 1, 0, calls...)
+	r.ErrorContains(err, "rpc down")
+	r.Equal(time.Millisecond, caller.cooldownFor(0))
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	r := require.New(t)
+
+	r.True(IsRateLimitError(errors.New("429 Too Many Requests")))
+	r.True(IsRateLimitError(errors.New("Your app has exceeded its compute units per second capacity")))
+	r.False(IsRateLimitError(errors.New("execution reverted")))
+}
@@ -0,0 +1,56 @@
+package multicall
+
+import "fmt"
+
+// Batch builds calls. The methods are checked against the contract ABI when they are
+// added and the first error is returned by Build.
+type Batch struct {
+	calls []*Call
+	err   error
+}
+
+// NewBatch creates a new batch.
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Add adds a call which has no outputs, so only the raw return data of the call is kept
+// and can be decoded with DecodeInto or DecodeAll.
+func (batch *Batch) Add(contract *Contract, method string, inputs ...any) *Batch {
+	return batch.AddWithOutputs(contract, nil, method, inputs...)
+}
+
+// AddFailable adds a call like Add but the call is allowed to fail.
+func (batch *Batch) AddFailable(contract *Contract, method string, inputs ...any) *Batch {
+	batch.Add(contract, method, inputs...)
+	if batch.err == nil {
+		batch.calls[len(batch.calls)-1].CanFail = true
+	}
+	return batch
+}
+
+// AddWithOutputs adds a call with the outputs to unpack the results into.
+func (batch *Batch) AddWithOutputs(contract *Contract, outputs any, method string, inputs ...any) *Batch {
+	if batch.err != nil {
+		return batch
+	}
+	index := len(batch.calls)
+	if contract == nil || contract.ABI == nil {
+		batch.err = fmt.Errorf("call at index [%d] has no contract abi", index)
+		return batch
+	}
+	if _, ok := contract.ABI.Methods[method]; !ok {
+		batch.err = fmt.Errorf("method '%s' of call at index [%d] not found in abi", method, index)
+		return batch
+	}
+	batch.calls = append(batch.calls, contract.NewCall(outputs, method, inputs...))
+	return batch
+}
+
+// Build returns the calls or the first error of the added calls.
+func (batch *Batch) Build() ([]*Call, error) {
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	return batch.calls, nil
+}
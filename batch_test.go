@@ -0,0 +1,41 @@
+package multicall
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	calls, err := NewBatch().
+		Add(testContract, "testFunc", true).
+		AddFailable(testContract, "testFunc", false).
+		AddWithOutputs(testContract, new(boolOutput), "testFunc", true).
+		Build()
+	r.NoError(err)
+	r.Len(calls, 3)
+	r.False(calls[0].CanFail)
+	r.True(calls[1].CanFail)
+
+	calls, err = (&Caller{contract: echoStub()}).Call(nil, calls...)
+	r.NoError(err)
+	var val bool
+	r.NoError(calls[0].DecodeInto(&val))
+	r.True(val)
+	r.True(calls[2].Outputs.(*boolOutput).Val1)
+
+	_, err = NewBatch().
+		Add(testContract, "testFunc", true).
+		Add(testContract, "tsetFunc", true).
+		AddFailable(testContract, "testFunc", true).
+		Build()
+	r.EqualError(err, "method 'tsetFunc' of call at index [1] not found in abi")
+
+	_, err = NewBatch().Add(nil, "testFunc").Build()
+	r.EqualError(err, "call at index [0] has no contract abi")
+}
@@ -0,0 +1,23 @@
+package multicall
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BroadcastCall creates a call of the same method with the same arguments for each
+// target, e.g. for reading the same value of many tokens. The inputs are packed once and
+// the prepacked calldata is shared by the calls, see Call.Prepack. The calls have no
+// outputs, so their raw return data can be decoded with DecodeInto.
+func BroadcastCall(contract *Contract, method string, args []interface{}, targets []common.Address) ([]*Call, error) {
+	first := contract.NewCall(nil, method, args...)
+	if err := first.Prepack(); err != nil {
+		return nil, err
+	}
+	calls := make([]*Call, len(targets))
+	for i, target := range targets {
+		call := *first
+		call.Contract = &Contract{ABI: contract.ABI, Address: target}
+		calls[i] = &call
+	}
+	return calls, nil
+}
@@ -0,0 +1,41 @@
+package multicall
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastCall(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	targets := []common.Address{common.HexToAddress(testAddr1), common.HexToAddress(testAddr2)}
+	calls, err := BroadcastCall(testContract, "testFunc", []interface{}{true}, targets)
+	r.NoError(err)
+	r.Len(calls, 2)
+	r.Equal(testContract.Address, calls[0].Contract.Address)
+	r.Equal(targets[1], calls[1].Contract.Address)
+
+	expected, err := testContract.NewCall(nil, "testFunc", true).Pack()
+	r.NoError(err)
+	for _, call := range calls {
+		b, err := call.Pack()
+		r.NoError(err)
+		r.Equal(expected, b)
+	}
+	r.Same(&calls[0].callData[0], &calls[1].callData[0])
+
+	caller := &Caller{contract: echoStub()}
+	_, err = caller.Call(nil, calls...)
+	r.NoError(err)
+	var out boolOutput
+	r.NoError(calls[1].DecodeInto(&out))
+	r.True(out.Val1)
+
+	_, err = BroadcastCall(testContract, "testFunc", []interface{}{'a'}, targets)
+	r.ErrorContains(err, "failed to pack 'testFunc' inputs")
+}
@@ -0,0 +1,127 @@
+package multicall
+
+import (
+	"container/list"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+)
+
+// Cache caches the return data of the calls pinned to a block. The keys are the hashes of
+// the block number, the target and the calldata. A cache must be safe for concurrent use.
+type Cache interface {
+	Get(key common.Hash) ([]byte, bool)
+	Set(key common.Hash, returnData []byte)
+}
+
+func cacheKey(blockNumber *big.Int, call contract_multicall.Multicall3Call3) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(blockNumber).Bytes(), call.Target.Bytes(), call.CallData)
+}
+
+// cacheable tells if the results of the calls with given options can be cached. The
+// results of the latest and pending blocks change, so only the calls pinned to a block
+// are cached.
+func (caller *Caller) cacheable(opts *bind.CallOpts) bool {
+	return caller.cache != nil && opts != nil && opts.BlockNumber != nil && opts.BlockNumber.Sign() >= 0
+}
+
+// fromCache sets the results of the cached calls and returns the calls which are not cached.
+func (caller *Caller) fromCache(opts *bind.CallOpts, calls []*Call, multiCalls []contract_multicall.Multicall3Call3) ([]*Call, []contract_multicall.Multicall3Call3, error) {
+	if !caller.cacheable(opts) {
+		return calls, multiCalls, nil
+	}
+	var (
+		missedCalls      []*Call
+		missedMultiCalls []contract_multicall.Multicall3Call3
+	)
+	for i, call := range calls {
+		returnData, ok := caller.cache.Get(cacheKey(opts.BlockNumber, multiCalls[i]))
+		if !ok {
+			missedCalls = append(missedCalls, call)
+			missedMultiCalls = append(missedMultiCalls, multiCalls[i])
+			continue
+		}
+		call.Failed = false
+		call.setRawReturn(returnData)
+		call.UnpackErr = nil
+		if err := caller.unpackCall(i, call, returnData); err != nil {
+			return nil, nil, err
+		}
+	}
+	return missedCalls, missedMultiCalls, nil
+}
+
+// toCache caches the results of the calls which succeeded.
+func (caller *Caller) toCache(opts *bind.CallOpts, calls []*Call, multiCalls []contract_multicall.Multicall3Call3) {
+	if !caller.cacheable(opts) {
+		return
+	}
+	for i, call := range calls {
+		if !call.Failed {
+			caller.cache.Set(cacheKey(opts.BlockNumber, multiCalls[i]), call.RawReturn)
+		}
+	}
+}
+
+// LRUCache is an in-memory cache which evicts the least recently used entries.
+type LRUCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[common.Hash]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key        common.Hash
+	returnData []byte
+}
+
+// NewLRUCache creates a new LRU cache which holds up to size entries.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		entries: make(map[common.Hash]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get implements Cache.
+func (cache *LRUCache) Get(key common.Hash) ([]byte, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	elem, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	cache.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).returnData, true
+}
+
+// Set implements Cache.
+func (cache *LRUCache) Set(key common.Hash, returnData []byte) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if elem, ok := cache.entries[key]; ok {
+		elem.Value.(*lruEntry).returnData = returnData
+		cache.order.MoveToFront(elem)
+		return
+	}
+	cache.entries[key] = cache.order.PushFront(&lruEntry{key: key, returnData: returnData})
+	for cache.order.Len() > cache.size {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (cache *LRUCache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.order.Len()
+}
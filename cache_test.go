@@ -0,0 +1,90 @@
+package multicall
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_Cache(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	otherContract, err := NewContract(oneValueABI, testAddr2)
+	r.NoError(err)
+
+	var sent int
+	stub := echoStub()
+	stub.failures = map[int]bool{2: true}
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		sent += len(calls)
+		return nil
+	}
+	cache := NewLRUCache(10)
+	caller := &Caller{contract: stub, cache: cache}
+
+	newCalls := func() []*Call {
+		return []*Call{
+			testContract.NewCall(new(boolOutput), "testFunc", true),
+			testContract.NewCall(new(boolOutput), "testFunc", false),
+			otherContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure(),
+		}
+	}
+	opts := &bind.CallOpts{BlockNumber: big.NewInt(testBlockNumber)}
+
+	_, err = caller.Call(opts, newCalls()...)
+	r.Error(err) // the last call failed
+	r.Equal(3, sent)
+	r.Equal(2, cache.Len())
+
+	// the failed call is not cached
+	stub.failures = nil
+	calls, err := caller.Call(opts, newCalls()...)
+	r.NoError(err)
+	r.Equal(4, sent)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.False(calls[1].Outputs.(*boolOutput).Val1)
+
+	// all cached
+	calls, err = caller.Call(opts, newCalls()...)
+	r.NoError(err)
+	r.Equal(4, sent)
+	r.True(calls[2].Outputs.(*boolOutput).Val1)
+
+	// another block and the latest block are not served from the cache
+	_, err = caller.Call(&bind.CallOpts{BlockNumber: big.NewInt(testBlockNumber + 1)}, newCalls()...)
+	r.NoError(err)
+	r.Equal(7, sent)
+	_, err = caller.Call(nil, newCalls()...)
+	r.NoError(err)
+	r.Equal(10, sent)
+	r.Equal(6, cache.Len())
+}
+
+func TestLRUCache(t *testing.T) {
+	r := require.New(t)
+
+	key1, key2, key3 := common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3")
+	cache := NewLRUCache(2)
+	cache.Set(key1, []byte{1})
+	cache.Set(key2, []byte{2})
+
+	returnData, ok := cache.Get(key1)
+	r.True(ok)
+	r.Equal([]byte{1}, returnData)
+
+	// key2 is the least recently used
+	cache.Set(key3, []byte{3})
+	_, ok = cache.Get(key2)
+	r.False(ok)
+	_, ok = cache.Get(key1)
+	r.True(ok)
+	_, ok = cache.Get(key3)
+	r.True(ok)
+	r.Equal(2, cache.Len())
+}
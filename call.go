@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 // Contract wraps the parsed ABI and acts as a call factory.
@@ -37,19 +39,33 @@ func ParseABI(rawJson string) (*abi.ABI, error) {
 	return &parsed, nil
 }
 
-// Call wraps a multicall call.
+// Call wraps a multicall call. Empty is set when the call returned no data, e.g. when the
+// target has no code, so that it can be told apart from a call which returned zero values.
 type Call struct {
-	CallName string
-	Contract *Contract
-	Method   string
-	Inputs   []any
-	Outputs  any
-	CanFail  bool
-	Failed   bool
+	CallName    string
+	Contract    *Contract
+	Method      string
+	Inputs      []any
+	Outputs     any
+	CanFail     bool
+	Failed      bool
+	Value       *big.Int
+	RawReturn   []byte
+	Empty       bool
+	UnpackErr   error
+	GasEstimate uint64
+	Gas         uint64
+	GasUsed     uint64
+	Index       int
+
+	raw       bool
+	prepacked bool
+	callData  []byte
 }
 
 // NewCall creates a new call using given inputs.
-// Outputs type is the expected output struct to unpack and set values in.
+// Outputs type is the expected output struct to unpack and set values in. If outputs
+// is nil, only the raw return data is kept, which can be decoded with DecodeInto.
 func (contract *Contract) NewCall(
 	outputs any, methodName string, inputs ...any,
 ) *Call {
@@ -61,6 +77,18 @@ func (contract *Contract) NewCall(
 	}
 }
 
+// NewRawCall creates a new call from already packed calldata. The call has no ABI, so
+// Pack returns the given calldata and Unpack only keeps the raw return data which is
+// available as RawReturn.
+func NewRawCall(target common.Address, data []byte, canFail bool) *Call {
+	return &Call{
+		Contract: &Contract{Address: target},
+		CanFail:  canFail,
+		raw:      true,
+		callData: data,
+	}
+}
+
 // Name sets a name for the call.
 func (call *Call) Name(name string) *Call {
 	call.CallName = name
@@ -68,14 +96,82 @@ func (call *Call) Name(name string) *Call {
 }
 
 // AllowFailure sets if the call is allowed to fail. This helps avoiding a revert
-// when one of the calls in the array fails.
+// when one of the calls in the array fails. The call is then marked Failed, and the error
+// returned by Caller.Call is still a *MultiError which matches ErrCallFailed, so the
+// results of the other calls can be used by checking Failed instead of giving up.
 func (call *Call) AllowFailure() *Call {
 	call.CanFail = true
 	return call
 }
 
+// WithValue sets the amount of ETH forwarded with the call. This is only used by
+// Caller.CallValue.
+func (call *Call) WithValue(value *big.Int) *Call {
+	call.Value = value
+	return call
+}
+
+// WithGasEstimate sets the estimated gas of the call. This is only used by
+// Caller.CallGasLimited.
+func (call *Call) WithGasEstimate(gas uint64) *Call {
+	call.GasEstimate = gas
+	return call
+}
+
+// WithIndex sets the sequence index of the call, which is used by SortByIndex for
+// ordering the calls independently of the order they were added in.
+func (call *Call) WithIndex(index int) *Call {
+	call.Index = index
+	return call
+}
+
+// WithGas sets the gas limit of the call. Since aggregate3 does not take a gas limit for
+// each call, a call with a gas limit is sent separately with eth_call by Caller.Call.
+func (call *Call) WithGas(gas uint64) *Call {
+	call.Gas = gas
+	return call
+}
+
+// selector returns the 4-byte method selector of the call, or nil when it is unknown.
+func (call *Call) selector() []byte {
+	if call.raw || call.prepacked {
+		if len(call.callData) < 4 {
+			return nil
+		}
+		return call.callData[:4]
+	}
+	if call.Contract == nil || call.Contract.ABI == nil {
+		return nil
+	}
+	method, ok := call.Contract.ABI.Methods[call.Method]
+	if !ok {
+		return nil
+	}
+	return method.ID
+}
+
+// setRawReturn sets the raw return data of the call and whether it is empty.
+func (call *Call) setRawReturn(data []byte) {
+	call.RawReturn = data
+	call.Empty = len(data) == 0
+}
+
+// clone copies the call with new outputs of the same type, so the copy can be made
+// without overwriting the results of the call.
+func (call *Call) clone() *Call {
+	callCopy := *call
+	if outputs := reflect.ValueOf(call.Outputs); outputs.Kind() == reflect.Pointer && !outputs.IsNil() {
+		callCopy.Outputs = reflect.New(outputs.Type().Elem()).Interface()
+	}
+	return &callCopy
+}
+
 // Unpack unpacks and converts EVM outputs and sets struct fields.
 func (call *Call) Unpack(b []byte) error {
+	if call.raw || call.Outputs == nil {
+		return nil
+	}
+
 	t := reflect.ValueOf(call.Outputs)
 	if t.Kind() == reflect.Pointer {
 		t = t.Elem()
@@ -90,20 +186,99 @@ func (call *Call) Unpack(b []byte) error {
 	}
 
 	fieldCount := t.NumField()
+	if fieldCount > len(out) {
+		return fmt.Errorf("outputs struct has %d fields but '%s' has %d outputs", fieldCount, call.Method, len(out))
+	}
 	for i := 0; i < fieldCount; i++ {
-		field := t.Field(i)
-		converted := abi.ConvertType(out[i], field.Interface())
-		field.Set(reflect.ValueOf(converted))
+		if err := assign(t.Field(i), out[i]); err != nil {
+			return fmt.Errorf("failed to set field '%s' of '%s' outputs: %v", t.Type().Field(i).Name, call.Method, err)
+		}
 	}
 
 	return nil
 }
 
-// Pack converts and packs EVM inputs.
+// Pack converts and packs EVM inputs. The calldata of a prepacked call is returned as is.
 func (call *Call) Pack() ([]byte, error) {
+	if call.raw || call.prepacked {
+		return call.callData, nil
+	}
 	b, err := call.Contract.ABI.Pack(call.Method, call.Inputs...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack '%s' inputs: %v", call.Method, err)
 	}
 	return b, nil
 }
+
+// Prepack packs the inputs once and keeps the calldata, so the call is not packed again
+// each time it is made, e.g. when the same calls are made at many blocks. The kept calldata
+// does not follow later changes of the method or the inputs, so Prepack should be called
+// again after changing them.
+func (call *Call) Prepack() error {
+	if call.raw {
+		return nil
+	}
+	call.prepacked = false
+	b, err := call.Pack()
+	if err != nil {
+		return err
+	}
+	call.callData = b
+	call.prepacked = true
+	return nil
+}
+
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// panicReasons describe the Solidity panic codes.
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized internal function",
+}
+
+// RevertReason decodes the raw return data of a failed call. Error(string) reverts are decoded
+// to the reason string and Panic(uint256) reverts are decoded to a description of the panic
+// code. Any other return data is returned as hex.
+func (call *Call) RevertReason() (string, error) {
+	if !call.Failed {
+		return "", errors.New("call did not fail")
+	}
+
+	data := call.RawReturn
+	if len(data) < 4 {
+		return hexutil.Encode(data), nil
+	}
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to unpack revert reason: %v", err)
+		}
+		return reason, nil
+
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) != 36 {
+			return "", errors.New("invalid panic data length")
+		}
+		code := new(big.Int).SetBytes(data[4:])
+		if reason, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			return fmt.Sprintf("panic: %s (0x%x)", reason, code), nil
+		}
+		return fmt.Sprintf("panic: unknown code (0x%x)", code), nil
+
+	default:
+		return hexutil.Encode(data), nil
+	}
+}
@@ -1,8 +1,12 @@
 package multicall
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,3 +39,152 @@ func TestCall_BadABI(t *testing.T) {
 	r.Error(err)
 	r.ErrorContains(err, "unexpected EOF")
 }
+
+func TestCall_RevertReason(t *testing.T) {
+	stringType, _ := abi.NewType("string", "", nil)
+	uintType, _ := abi.NewType("uint256", "", nil)
+	errorData, _ := abi.Arguments{{Type: stringType}}.Pack("insufficient balance")
+	panicData, _ := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x11))
+	unknownPanicData, _ := abi.Arguments{{Type: uintType}}.Pack(big.NewInt(0x99))
+
+	testCases := []struct {
+		name       string
+		returnData []byte
+		expected   string
+	}{
+		{
+			name:       "error string",
+			returnData: append(append([]byte{}, errorSelector...), errorData...),
+			expected:   "insufficient balance",
+		},
+		{
+			name:       "panic",
+			returnData: append(append([]byte{}, panicSelector...), panicData...),
+			expected:   "panic: arithmetic overflow or underflow (0x11)",
+		},
+		{
+			name:       "unknown panic",
+			returnData: append(append([]byte{}, panicSelector...), unknownPanicData...),
+			expected:   "panic: unknown code (0x99)",
+		},
+		{
+			name:       "custom error",
+			returnData: []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+			expected:   "0x0102030405",
+		},
+		{
+			name:       "empty",
+			returnData: nil,
+			expected:   "0x",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			call := &Call{Failed: true, RawReturn: testCase.returnData}
+			reason, err := call.RevertReason()
+			r.NoError(err)
+			r.Equal(testCase.expected, reason)
+		})
+	}
+}
+
+func TestCall_RevertReasonNotFailed(t *testing.T) {
+	r := require.New(t)
+
+	_, err := (&Call{}).RevertReason()
+	r.Error(err)
+}
+
+func TestCall_Raw(t *testing.T) {
+	r := require.New(t)
+
+	data := []byte{0x01, 0x02, 0x03, 0x04, 0x05}
+	call := NewRawCall(common.HexToAddress(testAddr1), data, true)
+	r.True(call.CanFail)
+	r.Equal(common.HexToAddress(testAddr1), call.Contract.Address)
+
+	b, err := call.Pack()
+	r.NoError(err)
+	r.Equal(data, b)
+
+	r.NoError(call.Unpack([]byte{0x06}))
+	r.Error(call.DecodeInto(new(bool)))
+}
+
+func TestCall_Prepack(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call := testContract.NewCall(new(boolOutput), "testFunc", true)
+	r.NoError(call.Prepack())
+	packed, err := call.Pack()
+	r.NoError(err)
+
+	// the kept calldata is used until the call is prepacked again
+	call.Inputs = []any{false}
+	b, err := call.Pack()
+	r.NoError(err)
+	r.Equal(packed, b)
+
+	caller := &Caller{contract: echoStub()}
+	_, err = caller.Call(nil, call)
+	r.NoError(err)
+	r.True(call.Outputs.(*boolOutput).Val1)
+
+	r.NoError(call.Prepack())
+	_, err = caller.Call(nil, call)
+	r.NoError(err)
+	r.False(call.Outputs.(*boolOutput).Val1)
+
+	call.Inputs = []any{'a'} // bad input
+	r.Error(call.Prepack())
+	_, err = call.Pack()
+	r.Error(err)
+}
+
+func TestCall_UnpackMismatch(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	returnData := common.LeftPadBytes([]byte{1}, 32)
+
+	call := testContract.NewCall(new(struct{ Val1 string }), "testFunc", true)
+	r.ErrorContains(call.Unpack(returnData), "failed to set field 'Val1' of 'testFunc' outputs")
+
+	call = testContract.NewCall(new(struct{ Val1, Val2 bool }), "testFunc", true)
+	r.EqualError(call.Unpack(returnData), "outputs struct has 2 fields but 'testFunc' has 1 outputs")
+
+	call = testContract.NewCall(new(boolOutput), "testFunc", true)
+	r.NoError(call.Unpack(returnData))
+	r.True(call.Outputs.(*boolOutput).Val1)
+}
+
+func TestCall_Empty(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	stub := echoStub()
+	stub.failures = map[int]bool{2: true}
+	caller := &Caller{contract: stub}
+
+	// the stub returns no data for the calldata with only a selector
+	calls, err := caller.Call(nil,
+		testContract.NewCall(new(boolOutput), "testFunc", false),
+		NewRawCall(common.HexToAddress(testAddr2), []byte{0x01, 0x02, 0x03, 0x04}, false),
+		NewRawCall(common.HexToAddress(testAddr2), []byte{0x01, 0x02, 0x03, 0x04, 0x05}, true),
+	)
+	r.Error(err)
+	r.False(calls[0].Empty)
+	r.False(calls[0].Outputs.(*boolOutput).Val1)
+	r.True(calls[1].Empty)
+	r.True(calls[2].Failed)
+	r.False(calls[2].Empty)
+}
@@ -2,12 +2,19 @@ package multicall
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
 )
 
@@ -15,87 +22,664 @@ import (
 // Taken from https://github.com/mds1/multicall
 const DefaultAddress = "0xcA11bde05977b3631167028862bE2a173976CA11"
 
-// Caller makes multicalls.
+// defaultMaxCallsPerAggregate is the largest number of calls made with a single multicall
+// by Call unless another limit is set with WithMaxCallsPerAggregate.
+const defaultMaxCallsPerAggregate = 10000
+
+// ChainDefaults are the multicall addresses of the chains where the multicall contract is
+// not deployed at DefaultAddress, by the chain IDs. The constructors look up the address
+// of the chain of the client unless an address is given, if ChainDefaults is not empty
+// and the client can get the chain ID, like the clients of the dialing functions. It is empty by default and
+// should only be modified before dialing.
+var ChainDefaults = map[uint64]string{}
+
+// defaultChunkSize is the chunk size used by the helpers which chunk calls internally
+// unless another size is set with WithDefaultChunkSize.
+const defaultChunkSize = 1000
+
+// helperChunkSize returns the chunk size of the helpers which chunk calls internally.
+func (caller *Caller) helperChunkSize() int {
+	if caller.chunkSize <= 0 {
+		return defaultChunkSize
+	}
+	return caller.chunkSize
+}
+
+// checkChunkSize rejects the chunk sizes which are not positive.
+func checkChunkSize(chunkSize int) error {
+	if chunkSize <= 0 {
+		return fmt.Errorf("%w: %d, must be positive", ErrInvalidChunkSize, chunkSize)
+	}
+	return nil
+}
+
+// Caller makes multicalls. A Caller is safe for concurrent use by multiple goroutines since
+// its configuration is not modified after it is created. The logger, the hooks and the
+// limiter set with the options must also be safe for concurrent use. The calls are
+// updated with their results, so the same calls must not be made concurrently.
 type Caller struct {
-	contract contract_multicall.Interface
+	client       bind.ContractCaller
+	rpc          rpcCaller
+	address      common.Address
+	contract     contract_multicall.Interface
+	transactor   contract_multicall.TransactorInterface
+	strict       bool
+	chunkSize    int
+	cooldown     time.Duration
+	logger       Logger
+	chunkHook    ChunkHook
+	limiter      Limiter
+	progress     func(completed, total int)
+	chunkTimeout time.Duration
+	minChunkSize int
+	maxChunkSize int
+	cache        Cache
+	splitFloor   int
+	isSizeError  func(err error) bool
+	errorABIs    []*abi.ABI
+	backpressure *backpressure
+	addressSet   bool
+	maxCalls     int
+	defaultCtx   context.Context
+	maxFailures  int
+	closer       func()
+	chunkTracer  ChunkTracer
+	defaultGas   uint64
+}
+
+// New creates a new caller with given options. If the client is also a
+// bind.ContractTransactor, the caller can send transactions with CallValue.
+func New(client bind.ContractCaller, opts ...Option) (*Caller, error) {
+	return NewContext(context.Background(), client, opts...)
 }
 
-// New creates a new caller.
-func New(client bind.ContractCaller, multicallAddr ...string) (*Caller, error) {
-	addr := DefaultAddress
-	if multicallAddr != nil {
-		addr = multicallAddr[0]
+// NewBackend creates a new caller like New with a full contract backend, so the caller
+// can both make calls and send transactions with CallValue. New is enough for the callers
+// which only make calls.
+func NewBackend(backend bind.ContractBackend, opts ...Option) (*Caller, error) {
+	return New(backend, opts...)
+}
+
+// NewContext is like New but takes a context for the requests made while creating the
+// caller, which is the chain ID lookup of ChainDefaults.
+func NewContext(ctx context.Context, client bind.ContractCaller, opts ...Option) (*Caller, error) {
+	caller := &Caller{
+		client:    client,
+		address:   common.HexToAddress(DefaultAddress),
+		chunkSize: defaultChunkSize,
 	}
-	contract, err := contract_multicall.NewMulticallCaller(common.HexToAddress(addr), client)
-	if err != nil {
+	for _, opt := range opts {
+		opt(caller)
+	}
+
+	if err := caller.bindContract(); err != nil {
 		return nil, err
 	}
-	return &Caller{
-		contract: contract,
-	}, nil
+	if err := caller.useChainDefault(ctx); err != nil {
+		return nil, err
+	}
+	return caller, nil
+}
+
+// MulticallContract is the multicall contract which the caller depends on. It can be
+// implemented by fakes in tests, like the one in the multicalltest package.
+type MulticallContract = contract_multicall.Interface
+
+// NewWithContract creates a new caller which uses given contract instead of binding the
+// multicall contract to a client. This is mostly useful for testing with fakes. The
+// caller has no backend client, so it cannot make the calls which need one.
+func NewWithContract(contract MulticallContract, opts ...Option) *Caller {
+	caller := &Caller{
+		address:   common.HexToAddress(DefaultAddress),
+		contract:  contract,
+		chunkSize: defaultChunkSize,
+	}
+	for _, opt := range opts {
+		opt(caller)
+	}
+	caller.contract = caller.withDefaultContext(contract)
+	return caller
+}
+
+// bindContract binds the multicall contract at the caller address to the client.
+func (caller *Caller) bindContract() error {
+	contract, err := contract_multicall.NewMulticallCaller(caller.address, caller.client)
+	if err != nil {
+		return err
+	}
+	caller.contract = caller.withDefaultContext(contract)
+	caller.transactor = nil
+	if transactor, ok := caller.client.(bind.ContractTransactor); ok {
+		caller.transactor, err = contract_multicall.NewMulticallTransactor(caller.address, transactor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Dial dials and Ethereum JSON-RPC API and uses the client as the
 // caller backend.
-func Dial(ctx context.Context, rawUrl string, multicallAddr ...string) (*Caller, error) {
-	client, err := ethclient.DialContext(ctx, rawUrl)
+func Dial(ctx context.Context, rawUrl string, opts ...Option) (*Caller, error) {
+	rpcClient, err := rpc.DialContext(ctx, rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	caller, err := newFromRPC(ctx, rpcClient, opts...)
+	if err != nil {
+		rpcClient.Close()
+		return nil, err
+	}
+	caller.closer = closeOnce(rpcClient.Close)
+	return caller, nil
+}
+
+// DialWithHeaders is like Dial but sends the given headers with each HTTP request or with
+// the websocket handshake, e.g. the API key headers of the providers.
+func DialWithHeaders(ctx context.Context, rawUrl string, headers map[string]string, opts ...Option) (*Caller, error) {
+	httpHeaders := make(http.Header, len(headers))
+	for key, value := range headers {
+		httpHeaders.Set(key, value)
+	}
+	rpcClient, err := rpc.DialOptions(ctx, rawUrl, rpc.WithHeaders(httpHeaders))
+	if err != nil {
+		return nil, err
+	}
+	caller, err := newFromRPC(ctx, rpcClient, opts...)
 	if err != nil {
+		rpcClient.Close()
+		return nil, err
+	}
+	caller.closer = closeOnce(rpcClient.Close)
+	return caller, nil
+}
+
+// NewFromRPC creates a new caller which uses the already connected RPC client as the
+// caller backend, e.g. a client with a custom transport. It is otherwise the same as New.
+func NewFromRPC(rpcClient *rpc.Client, opts ...Option) (*Caller, error) {
+	return newFromRPC(context.Background(), rpcClient, opts...)
+}
+
+func newFromRPC(ctx context.Context, rpcClient *rpc.Client, opts ...Option) (*Caller, error) {
+	caller, err := NewContext(ctx, ethclient.NewClient(rpcClient), opts...)
+	if err != nil {
+		return nil, err
+	}
+	caller.rpc = rpcClient
+	return caller, nil
+}
+
+// useChainDefault binds the caller to the address of the connected chain in ChainDefaults
+// unless an address was given.
+func (caller *Caller) useChainDefault(ctx context.Context) error {
+	if caller.addressSet || len(ChainDefaults) == 0 {
+		return nil
+	}
+	reader, ok := caller.client.(chainIDReader)
+	if !ok {
+		return nil
+	}
+	chainID, err := reader.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain id: %v", err)
+	}
+	addr, ok := ChainDefaults[chainID.Uint64()]
+	if !ok {
+		return nil
+	}
+	caller.address = common.HexToAddress(addr)
+	return caller.bindContract()
+}
+
+// DialVerified is like Dial but also makes sure that the multicall contract is deployed.
+func DialVerified(ctx context.Context, rawUrl string, opts ...Option) (*Caller, error) {
+	caller, err := Dial(ctx, rawUrl, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := caller.verify(ctx); err != nil {
+		caller.Close()
+		return nil, err
+	}
+	return caller, nil
+}
+
+type chainIDReader interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// verify makes sure that the multicall contract is deployed.
+func (caller *Caller) verify(ctx context.Context) error {
+	deployed, err := caller.isDeployed(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	if deployed {
+		return nil
+	}
+	chain := "unknown chain"
+	if reader, ok := caller.client.(chainIDReader); ok {
+		if chainID, err := reader.ChainID(ctx); err == nil {
+			chain = fmt.Sprintf("chain %s", chainID)
+		}
+	}
+	return fmt.Errorf("no multicall contract deployed at %s on %s", caller.address.Hex(), chain)
+}
+
+// Address returns the address of the multicall contract used by the caller.
+func (caller *Caller) Address() common.Address {
+	return caller.address
+}
+
+// ChunkSize returns the chunk size used by the helpers which chunk calls internally, as
+// set with WithDefaultChunkSize, so the helpers built on top of the caller can chunk the
+// same way.
+func (caller *Caller) ChunkSize() int {
+	return caller.helperChunkSize()
+}
+
+// Cooldown returns the cooldown between chunks used by the helpers which chunk calls
+// internally, as set with WithCooldown.
+func (caller *Caller) Cooldown() time.Duration {
+	return caller.cooldown
+}
+
+// Contract returns the multicall contract used by the caller. It is a
+// *contract_multicall.MulticallCaller for the callers created with New, which can be used
+// for the methods the caller does not wrap. The extra methods of a multicall fork can be
+// called through the caller with a Contract created from the ABI of the fork and the
+// caller address, or by passing the binding of the fork to NewWithContract.
+func (caller *Caller) Contract() MulticallContract {
+	return caller.contract
+}
+
+// Close closes the connection of a caller created by dialing, like with Dial. It is a
+// no-op for the callers which use a client given by the user, like the callers created with
+// New or NewFromRPC, and calling it more than once is safe. The copies of a caller, like
+// the one returned by Strict, share the connection, so closing any of them closes it.
+func (caller *Caller) Close() {
+	if caller.closer != nil {
+		caller.closer()
+	}
+}
+
+// closeOnce returns a function which closes the connection only the first time it is called.
+func closeOnce(close func()) func() {
+	var once sync.Once
+	return func() {
+		once.Do(close)
+	}
+}
+
+// Strict returns a copy of the caller which fails fast when packing or unpacking any of
+// the calls fails. By default, Call skips the calls which fail to pack, sets unpack errors
+// on each call as UnpackErr and returns all call failures as a *MultiError.
+func (caller *Caller) Strict() *Caller {
+	strictCaller := *caller
+	strictCaller.strict = true
+	return &strictCaller
+}
+
+// lenient returns a copy of the caller which is not strict.
+func (caller *Caller) lenient() *Caller {
+	lenientCaller := *caller
+	lenientCaller.strict = false
+	return &lenientCaller
+}
+
+// WithAddress returns a copy of the caller which uses the multicall contract at given
+// address with the same client and options.
+func (caller *Caller) WithAddress(addr common.Address) (*Caller, error) {
+	addrCaller := *caller
+	addrCaller.address = addr
+	if err := addrCaller.bindContract(); err != nil {
 		return nil, err
 	}
-	return New(client, multicallAddr...)
+	return &addrCaller, nil
 }
 
-// Call makes multicalls.
+// Call makes multicalls. The From address of the options is the msg.sender of the
+// multicall contract and the msg.sender of the calls is the multicall contract, see CallAs
+// for the calls which depend on msg.sender. The calls with a gas limit are sent separately
+// with eth_call since aggregate3 does not take a gas limit for each call. The successful
+// results of the calls pinned to a block are cached when the caller has a cache. Unless
+// the caller is strict, the returned error is a *MultiError when any of the calls fail to
+// pack, fail on chain or fail to unpack, including the calls which are allowed to fail, and
+// the other calls have their results. More calls than the limit set with
+// WithMaxCallsPerAggregate are rejected with ErrTooManyCalls.
 func (caller *Caller) Call(opts *bind.CallOpts, calls ...*Call) ([]*Call, error) {
-	var multiCalls []contract_multicall.Multicall3Call3
+	if err := caller.checkCallCount(calls); err != nil {
+		return calls, err
+	}
+	packedCalls, multiCalls, multiErr, err := caller.pack(calls)
+	if err != nil {
+		return calls, err
+	}
+	packedCalls, multiCalls, gasCalls, gasMultiCalls := splitGasCalls(packedCalls, multiCalls)
+	if err := caller.callWithGas(opts, calls, gasCalls, gasMultiCalls); err != nil {
+		return calls, err
+	}
+	packedCalls, multiCalls, err = caller.fromCache(opts, packedCalls, multiCalls)
+	if err != nil {
+		return calls, err
+	}
+	if len(packedCalls) > 0 || len(calls) == 0 {
+		if err := caller.aggregate3(opts, packedCalls, multiCalls); err != nil {
+			return calls, err
+		}
+		caller.toCache(opts, packedCalls, multiCalls)
+	}
+	collectCallErrors(&multiErr, calls)
+	return calls, multiErr.errOrNil()
+}
 
+// checkCallCount makes sure that the calls do not exceed the calls per aggregate limit.
+func (caller *Caller) checkCallCount(calls []*Call) error {
+	limit := caller.maxCalls
+	if limit == 0 {
+		limit = defaultMaxCallsPerAggregate
+	}
+	if limit > 0 && len(calls) > limit {
+		return fmt.Errorf("%w: %d calls exceed the limit of %d calls per multicall, use CallChunked instead", ErrTooManyCalls, len(calls), limit)
+	}
+	return nil
+}
+
+// pack packs the calls. Unless the caller is strict, the calls which fail to pack are left
+// out and the errors are recorded.
+func (caller *Caller) pack(calls []*Call) (packedCalls []*Call, multiCalls []contract_multicall.Multicall3Call3, multiErr MultiError, err error) {
+	if caller.strict {
+		multiCalls, err = packCalls3(calls)
+		return calls, multiCalls, multiErr, err
+	}
+	packedCalls, multiCalls, multiErr = packCallsLenient(calls)
+	return
+}
+
+// packCallsLenient packs the calls and leaves out the ones which fail to pack, recording
+// the errors instead.
+func packCallsLenient(calls []*Call) (packedCalls []*Call, multiCalls []contract_multicall.Multicall3Call3, multiErr MultiError) {
 	for i, call := range calls {
 		b, err := call.Pack()
 		if err != nil {
-			return calls, fmt.Errorf("failed to pack call inputs at index [%d]: %v", i, err)
+			multiErr.add(i, call, fmt.Errorf("failed to pack call inputs: %v", err))
+			continue
 		}
+		packedCalls = append(packedCalls, call)
 		multiCalls = append(multiCalls, contract_multicall.Multicall3Call3{
 			Target:       call.Contract.Address,
 			AllowFailure: call.CanFail,
 			CallData:     b,
 		})
 	}
+	return
+}
 
-	results, err := caller.contract.Aggregate3(opts, multiCalls)
-	if err != nil {
-		return calls, fmt.Errorf("multicall failed: %v", err)
+// unpackErrors returns the unpack failures of the calls as a *MultiError, or nil if there
+// are none. It is used by the methods which mark the calls failed on chain without an
+// error, like TryCall.
+func unpackErrors(calls []*Call) error {
+	var multiErr MultiError
+	for i, call := range calls {
+		if call.UnpackErr != nil {
+			multiErr.add(i, call, call.UnpackErr)
+		}
 	}
+	return multiErr.errOrNil()
+}
 
-	for i, result := range results {
-		call := calls[i] // index always matches
-		call.Failed = !result.Success
-		if err := call.Unpack(result.ReturnData); err != nil {
-			return calls, fmt.Errorf("failed to unpack call outputs at index [%d]: %v", i, err)
+// collectCallErrors records the on-chain and unpack failures of the calls.
+func collectCallErrors(multiErr *MultiError, calls []*Call) {
+	for i, call := range calls {
+		switch {
+		case call.Failed:
+			multiErr.add(i, call, ErrCallFailed)
+		case call.UnpackErr != nil:
+			multiErr.add(i, call, call.UnpackErr)
 		}
 	}
+	multiErr.sort()
+}
 
-	return calls, nil
+// CallNamed makes multicalls like Call and returns the calls by their names, which are
+// set with Call.Name. The names must be unique and non-empty.
+func (caller *Caller) CallNamed(opts *bind.CallOpts, calls ...*Call) (map[string]*Call, error) {
+	named := make(map[string]*Call, len(calls))
+	for i, call := range calls {
+		if call.CallName == "" {
+			return nil, fmt.Errorf("call at index [%d] has no name", i)
+		}
+		if _, ok := named[call.CallName]; ok {
+			return nil, fmt.Errorf("call at index [%d] has duplicate name '%s'", i, call.CallName)
+		}
+		named[call.CallName] = call
+	}
+
+	_, err := caller.Call(opts, calls...)
+	return named, err
 }
 
-// CallChunked makes multiple multicalls by chunking given calls.
-// Cooldown is helpful for sleeping between chunks and avoiding rate limits.
+// CallAt makes multicalls like Call at the given block number.
+func (caller *Caller) CallAt(blockNumber *big.Int, calls ...*Call) ([]*Call, error) {
+	ctx, _ := caller.callContext(nil)
+	return caller.Call(&bind.CallOpts{
+		Context:     ctx,
+		BlockNumber: blockNumber,
+	}, calls...)
+}
+
+// CallPending makes multicalls like Call against the pending state.
+func (caller *Caller) CallPending(calls ...*Call) ([]*Call, error) {
+	ctx, _ := caller.callContext(nil)
+	return caller.Call(&bind.CallOpts{
+		Context: ctx,
+		Pending: true,
+	}, calls...)
+}
+
+func (caller *Caller) aggregate3(opts *bind.CallOpts, calls []*Call, multiCalls []contract_multicall.Multicall3Call3) error {
+	results, err := caller.contract.Aggregate3(opts, multiCalls)
+	if err != nil {
+		return multicallError(err)
+	}
+	return caller.unpackResults(calls, results)
+}
+
+// CallChunked makes multiple multicalls by chunking given calls. The chunk size must be
+// positive, otherwise ErrInvalidChunkSize is returned, and a chunk size larger than the
+// number of calls makes a single chunk. The same applies to the other chunked methods
+// which take a chunk size.
+// Cooldown is helpful for sleeping between chunks and avoiding rate limits. The failure
+// of a whole chunk is returned as a *ChunkError which has the indexes of its calls. In that
+// case, only the calls of the chunks which were made before the failed chunk are returned
+// and they have their results, so the work done before the failure can be used. The calls
+// of the failed chunk and the later chunks are left out, unless WithCircuitBreaker is used.
+// The same applies to the other sequential chunked methods, like TryCallChunked and
+// CallChunkedRetry.
 func (caller *Caller) CallChunked(opts *bind.CallOpts, chunkSize int, cooldown time.Duration, calls ...*Call) ([]*Call, error) {
-	var allCalls []*Call
-	for i, chunk := range chunkInputs(chunkSize, calls) {
-		if i > 0 && cooldown > 0 {
-			time.Sleep(cooldown)
+	ctx, _ := caller.callContext(opts)
+	return caller.CallChunkedContext(ctx, opts, chunkSize, cooldown, calls...)
+}
+
+// CallChunkedContext is like CallChunked but stops waiting and returns the calls made
+// so far along with the context error when the context is cancelled. The context is also
+// used for the calls when opts has no context.
+func (caller *Caller) CallChunkedContext(ctx context.Context, opts *bind.CallOpts, chunkSize int, cooldown time.Duration, calls ...*Call) ([]*Call, error) {
+	if err := checkChunkSize(chunkSize); err != nil {
+		return calls, err
+	}
+	return caller.callChunks(ctx, opts, calls, ChunkSlice(chunkSize, calls), cooldown, func(chunkOpts *bind.CallOpts, chunk []*Call) ([]*Call, error) {
+		return caller.callSplitting(chunkOpts, chunk)
+	})
+}
+
+// callChunks makes the chunks in order. The options given to callChunk have the context
+// unless opts has its own context.
+func (caller *Caller) callChunks(
+	ctx context.Context, opts *bind.CallOpts, calls []*Call, chunks [][]*Call, cooldown time.Duration,
+	callChunk func(chunkOpts *bind.CallOpts, chunk []*Call) ([]*Call, error),
+) ([]*Call, error) {
+	var (
+		allCalls []*Call
+		multiErr MultiError
+		failures int
+	)
+	log := caller.log()
+	log.Debugf("multicall: making %d calls in %d chunks", len(calls), len(chunks))
+	for i, chunk := range chunks {
+		if d := caller.cooldownFor(cooldown); i > 0 && d > 0 {
+			if err := sleepContext(ctx, d); err != nil {
+				return allCalls, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return allCalls, err
+		}
+		if err := caller.wait(ctx); err != nil {
+			return allCalls, err
 		}
 
-		chunk, err := caller.Call(opts, chunk...)
-		if err != nil {
-			return calls, fmt.Errorf("call chunk [%d] failed: %v", i, err)
+		offset := len(allCalls)
+		start := time.Now()
+		chunkOpts, endTrace := caller.traceChunk(withContext(ctx, opts), i, chunk)
+		results, err := callChunk(chunkOpts, chunk)
+		endTrace(err)
+		log.Debugf("multicall: chunk [%d] with %d calls took %s", i, len(chunk), time.Since(start))
+		caller.chunkDone(i, len(chunk), start, err)
+		caller.observeRateLimit(err)
+		if chunkErr := (*MultiError)(nil); errors.As(err, &chunkErr) {
+			multiErr.merge(offset, chunkErr)
+		} else if err != nil {
+			chunkErr := newChunkError(i, offset, len(chunk), err)
+			if caller.maxFailures <= 0 {
+				return allCalls, chunkErr
+			}
+			failChunk(&multiErr, offset, chunk, chunkErr)
+			allCalls = append(allCalls, chunk...)
+			if failures++; failures >= caller.maxFailures {
+				multiErr.sort()
+				return allCalls, &circuitOpenError{failures: failures, err: chunkErr, multiErr: &multiErr}
+			}
+			caller.reportProgress(len(allCalls), len(calls))
+			continue
+		}
+		failures = 0
+		allCalls = append(allCalls, results...)
+		caller.reportProgress(len(allCalls), len(calls))
+	}
+	return allCalls, multiErr.errOrNil()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// withContext returns opts with the given context if opts has no context.
+func withContext(ctx context.Context, opts *bind.CallOpts) *bind.CallOpts {
+	if opts != nil && opts.Context != nil {
+		return opts
+	}
+	var newOpts bind.CallOpts
+	if opts != nil {
+		newOpts = *opts
+	}
+	newOpts.Context = ctx
+	return &newOpts
+}
+
+// CallConcurrent makes multiple multicalls by chunking given calls and dispatching the
+// chunks with up to maxWorkers goroutines. The returned calls are always in the given order.
+// The first error cancels the outstanding chunks and is returned.
+func (caller *Caller) CallConcurrent(opts *bind.CallOpts, chunkSize int, maxWorkers int, calls ...*Call) ([]*Call, error) {
+	if err := checkChunkSize(chunkSize); err != nil {
+		return calls, err
+	}
+	if maxWorkers <= 0 {
+		maxWorkers = 1
+	}
+
+	parentCtx, _ := caller.callContext(opts)
+	var baseOpts bind.CallOpts
+	if opts != nil {
+		baseOpts = *opts
+	}
+	ctx, cancel := context.WithCancel(parentCtx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		mu       sync.Mutex
+		multiErr MultiError
+	)
+	workers := make(chan struct{}, maxWorkers)
+	for i, chunk := range ChunkSlice(chunkSize, calls) {
+		offset := i * chunkSize
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
 		}
-		allCalls = append(allCalls, chunk...)
+		if err := caller.wait(ctx); err != nil {
+			<-workers
+			errOnce.Do(func() { firstErr = err })
+			break
+		}
+
+		wg.Add(1)
+		go func(i, offset int, chunk []*Call) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			chunkOpts := baseOpts
+			chunkOpts.Context = ctx
+			traceOpts, endTrace := caller.traceChunk(&chunkOpts, i, chunk)
+			timeoutOpts, cancelChunk := caller.withChunkTimeout(traceOpts)
+			defer cancelChunk()
+			// chunks share the underlying array with calls so results land in order
+			start := time.Now()
+			_, err := caller.Call(timeoutOpts, chunk...)
+			endTrace(err)
+			caller.chunkDone(i, len(chunk), start, err)
+			if chunkErr := (*MultiError)(nil); errors.As(err, &chunkErr) {
+				mu.Lock()
+				multiErr.merge(offset, chunkErr)
+				mu.Unlock()
+			} else if err != nil {
+				errOnce.Do(func() {
+					firstErr = newChunkError(i, offset, len(chunk), err)
+					cancel()
+				})
+			}
+		}(i, offset, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return calls, firstErr
 	}
-	return allCalls, nil
+	if err := parentCtx.Err(); err != nil {
+		return calls, err
+	}
+	multiErr.sort()
+	return calls, multiErr.errOrNil()
 }
 
-func chunkInputs[T any](chunkSize int, inputs []T) (chunks [][]T) {
+// ChunkSlice splits the inputs into chunks of chunkSize, with the remainder in the last
+// chunk. The inputs are returned in a single chunk if chunkSize is not positive or not
+// smaller than the number of inputs. The chunked methods of Caller chunk the calls the
+// same way.
+func ChunkSlice[T any](chunkSize int, inputs []T) (chunks [][]T) {
 	if len(inputs) == 0 {
 		return
 	}
@@ -123,50 +707,400 @@ func chunkInputs[T any](chunkSize int, inputs []T) (chunks [][]T) {
 	return
 }
 
+// TryCall makes multicalls using the tryAggregate method. Failed calls are marked on each
+// call unless requireSuccess is set, in which case the whole batch reverts. Unless the
+// caller is strict, the calls which fail to unpack are returned in a *MultiError.
 func (caller *Caller) TryCall(opts *bind.CallOpts, requireSuccess bool, calls ...*Call) ([]*Call, error) {
-	var multiCalls []contract_multicall.Multicall3Call
+	multiCalls, err := packCalls(calls)
+	if err != nil {
+		return calls, err
+	}
+
+	results, err := caller.contract.TryAggregate(opts, requireSuccess, multiCalls)
+	if err != nil {
+		return calls, multicallError(err)
+	}
+
+	if err := caller.unpackResults(calls, results); err != nil {
+		return calls, err
+	}
+
+	return calls, unpackErrors(calls)
+}
+
+// TryCallChunked makes multiple multicalls by chunking given calls using TryAggregate.
+// Cooldown is helpful for sleeping between chunks and avoiding rate limits.
+func (caller *Caller) TryCallChunked(opts *bind.CallOpts, requireSuccess bool, chunkSize int, cooldown time.Duration, calls ...*Call) ([]*Call, error) {
+	ctx, _ := caller.callContext(opts)
+	return caller.TryCallChunkedContext(ctx, opts, requireSuccess, chunkSize, cooldown, calls...)
+}
+
+// TryCallChunkedContext is like TryCallChunked but stops waiting and returns the calls
+// made so far along with the context error when the context is cancelled, like
+// CallChunkedContext. The context is also used for the calls when opts has no context.
+func (caller *Caller) TryCallChunkedContext(ctx context.Context, opts *bind.CallOpts, requireSuccess bool, chunkSize int, cooldown time.Duration, calls ...*Call) ([]*Call, error) {
+	if err := checkChunkSize(chunkSize); err != nil {
+		return calls, err
+	}
+	return caller.callChunks(ctx, opts, calls, ChunkSlice(chunkSize, calls), cooldown, func(chunkOpts *bind.CallOpts, chunk []*Call) ([]*Call, error) {
+		chunkOpts, cancel := caller.withChunkTimeout(chunkOpts)
+		defer cancel()
+		return caller.TryCall(chunkOpts, requireSuccess, chunk...)
+	})
+}
+
+// Aggregate makes multicalls using the aggregate method. The whole batch reverts if any
+// of the calls fail, so calls which allow failure are rejected. The block number included
+// in the response is returned along with the calls. Unless the caller is strict, the calls
+// which fail to unpack are returned in a *MultiError.
+func (caller *Caller) Aggregate(opts *bind.CallOpts, calls ...*Call) (uint64, []*Call, error) {
+	if err := checkNoFailure(calls, "aggregate"); err != nil {
+		return 0, calls, err
+	}
+
+	multiCalls, err := packCalls(calls)
+	if err != nil {
+		return 0, calls, err
+	}
+
+	result, err := caller.contract.Aggregate(opts, multiCalls)
+	if err != nil {
+		return 0, calls, multicallError(err)
+	}
+
+	for i, returnData := range result.ReturnData {
+		call := calls[i] // index always matches
+		// aggregate reverts when any of the calls fail
+		call.Failed = false
+		call.setRawReturn(returnData)
+		call.UnpackErr = nil
+		if err := caller.unpackCall(i, call, returnData); err != nil {
+			return 0, calls, err
+		}
+	}
+
+	return result.BlockNumber.Uint64(), calls, unpackErrors(calls)
+}
+
+// BlockAndAggregate makes multicalls using the blockAndAggregate method and returns the
+// number and the hash of the block the calls were made at. The whole batch reverts if any
+// of the calls fail, so calls which allow failure are rejected. Unless the caller is
+// strict, the calls which fail to unpack are returned in a *MultiError.
+func (caller *Caller) BlockAndAggregate(opts *bind.CallOpts, calls ...*Call) (blockNumber uint64, blockHash common.Hash, results []*Call, err error) {
+	if err := checkNoFailure(calls, "blockAndAggregate"); err != nil {
+		return 0, common.Hash{}, calls, err
+	}
+
+	multiCalls, err := packCalls(calls)
+	if err != nil {
+		return 0, common.Hash{}, calls, err
+	}
+
+	result, err := caller.contract.BlockAndAggregate(opts, multiCalls)
+	if err != nil {
+		return 0, common.Hash{}, calls, multicallError(err)
+	}
+
+	if err := caller.unpackResults(calls, result.ReturnData); err != nil {
+		return 0, common.Hash{}, calls, err
+	}
+
+	return result.BlockNumber.Uint64(), result.BlockHash, calls, unpackErrors(calls)
+}
+
+// TryBlockAndAggregate makes multicalls using the tryBlockAndAggregate method and returns the
+// number and the hash of the block the calls were made at. Failed calls are marked on each
+// call unless requireSuccess is set, in which case the whole batch reverts. Unless the
+// caller is strict, the calls which fail to unpack are returned in a *MultiError.
+func (caller *Caller) TryBlockAndAggregate(opts *bind.CallOpts, requireSuccess bool, calls ...*Call) (blockNumber uint64, blockHash common.Hash, results []*Call, err error) {
+	multiCalls, err := packCalls(calls)
+	if err != nil {
+		return 0, common.Hash{}, calls, err
+	}
+
+	result, err := caller.contract.TryBlockAndAggregate(opts, requireSuccess, multiCalls)
+	if err != nil {
+		return 0, common.Hash{}, calls, multicallError(err)
+	}
+
+	if err := caller.unpackResults(calls, result.ReturnData); err != nil {
+		return 0, common.Hash{}, calls, err
+	}
+
+	return result.BlockNumber.Uint64(), result.BlockHash, calls, unpackErrors(calls)
+}
+
+func checkNoFailure(calls []*Call, method string) error {
+	for i, call := range calls {
+		if call.CanFail {
+			return fmt.Errorf("call at index [%d] allows failure and cannot be used with %s", i, method)
+		}
+	}
+	return nil
+}
+
+func (caller *Caller) unpackResults(calls []*Call, results []contract_multicall.Multicall3Result) error {
+	for i, result := range results {
+		call := calls[i] // index always matches
+		call.Failed = !result.Success
+		call.setRawReturn(result.ReturnData)
+		call.UnpackErr = nil
+		if call.Failed {
+			continue // return data is the revert data
+		}
+		if err := caller.unpackCall(i, call, result.ReturnData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unpackCall unpacks the call outputs and returns an error only in strict mode.
+func (caller *Caller) unpackCall(i int, call *Call, returnData []byte) error {
+	if err := call.Unpack(returnData); err != nil {
+		call.UnpackErr = err
+		if caller.strict {
+			return fmt.Errorf("failed to unpack call outputs at index [%d] %s: %v", i, describeCall(call), err)
+		}
+	}
+	return nil
+}
+
+func packCalls3(calls []*Call) ([]contract_multicall.Multicall3Call3, error) {
+	var multiCalls []contract_multicall.Multicall3Call3
+	for i, call := range calls {
+		b, err := call.Pack()
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack call inputs at index [%d] %s: %v", i, describeCall(call), err)
+		}
+		multiCalls = append(multiCalls, contract_multicall.Multicall3Call3{
+			Target:       call.Contract.Address,
+			AllowFailure: call.CanFail,
+			CallData:     b,
+		})
+	}
+	return multiCalls, nil
+}
 
+func packCalls(calls []*Call) ([]contract_multicall.Multicall3Call, error) {
+	var multiCalls []contract_multicall.Multicall3Call
 	for i, call := range calls {
 		b, err := call.Pack()
 		if err != nil {
-			return calls, fmt.Errorf("failed to pack call inputs at index [%d]: %v", i, err)
+			return nil, fmt.Errorf("failed to pack call inputs at index [%d] %s: %v", i, describeCall(call), err)
 		}
 		multiCalls = append(multiCalls, contract_multicall.Multicall3Call{
 			Target:   call.Contract.Address,
 			CallData: b,
 		})
 	}
+	return multiCalls, nil
+}
 
-	results, err := caller.contract.TryAggregate(opts, requireSuccess, multiCalls)
+// CallValue sends a transaction which makes multicalls using the aggregate3Value method,
+// forwarding the value set on each call. The transaction value is the sum of the call values
+// and the provided opts.Value must either be nil or match it.
+func (caller *Caller) CallValue(opts *bind.TransactOpts, calls ...*Call) (*types.Transaction, error) {
+	if caller.transactor == nil {
+		return nil, errors.New("caller backend cannot send transactions")
+	}
+	if opts == nil {
+		return nil, errors.New("transact opts are required")
+	}
+
+	multiCalls, total, err := packCalls3Value(calls)
 	if err != nil {
-		return calls, fmt.Errorf("multicall failed: %v", err)
+		return nil, err
 	}
 
-	for i, result := range results {
-		call := calls[i] // index always matches
-		call.Failed = !result.Success
-		if err := call.Unpack(result.ReturnData); err != nil {
-			return calls, fmt.Errorf("failed to unpack call outputs at index [%d]: %v", i, err)
+	if opts.Value != nil && opts.Value.Cmp(total) != 0 {
+		return nil, fmt.Errorf("transact opts value %s does not match the sum of call values %s", opts.Value, total)
+	}
+	txOpts := *opts
+	txOpts.Value = total
+
+	tx, err := caller.transactor.Aggregate3Value(&txOpts, multiCalls)
+	if err != nil {
+		return nil, fmt.Errorf("multicall transaction failed: %v", err)
+	}
+	return tx, nil
+}
+
+// packCalls3Value packs the calls with their values and returns the sum of the values.
+func packCalls3Value(calls []*Call) ([]contract_multicall.Multicall3Call3Value, *big.Int, error) {
+	var multiCalls []contract_multicall.Multicall3Call3Value
+	total := new(big.Int)
+	for i, call := range calls {
+		b, err := call.Pack()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to pack call inputs at index [%d] %s: %v", i, describeCall(call), err)
 		}
+		value := call.Value
+		if value == nil {
+			value = new(big.Int)
+		}
+		total.Add(total, value)
+		multiCalls = append(multiCalls, contract_multicall.Multicall3Call3Value{
+			Target:       call.Contract.Address,
+			AllowFailure: call.CanFail,
+			Value:        value,
+			CallData:     b,
+		})
 	}
+	return multiCalls, total, nil
+}
 
-	return calls, nil
+// ChainID gets the chain ID by using the getChainId method of the multicall contract.
+func (caller *Caller) ChainID(opts *bind.CallOpts) (*big.Int, error) {
+	chainID, err := caller.contract.GetChainId(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain id: %v", err)
+	}
+	return chainID, nil
 }
 
-// TryCallChunked makes multiple multicalls by chunking given calls using TryAggregate.
-// Cooldown is helpful for sleeping between chunks and avoiding rate limits.
-func (caller *Caller) TryCallChunked(opts *bind.CallOpts, requireSuccess bool, chunkSize int, cooldown time.Duration, calls ...*Call) ([]*Call, error) {
-	var allCalls []*Call
-	for i, chunk := range chunkInputs(chunkSize, calls) {
-		if i > 0 && cooldown > 0 {
-			time.Sleep(cooldown)
+// BlockNumber gets the current block number by using the getBlockNumber method of the
+// multicall contract.
+func (caller *Caller) BlockNumber(opts *bind.CallOpts) (uint64, error) {
+	blockNumber, err := caller.contract.GetBlockNumber(opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block number: %v", err)
+	}
+	return blockNumber.Uint64(), nil
+}
+
+// BaseFee gets the base fee of the current block by using the getBasefee method of the
+// multicall contract. ErrBaseFeeUnsupported is returned on chains which do not support
+// EIP-1559.
+func (caller *Caller) BaseFee(opts *bind.CallOpts) (*big.Int, error) {
+	baseFee, err := caller.contract.GetBasefee(opts)
+	if err != nil {
+		if isRevert(err) {
+			return nil, fmt.Errorf("%w: %v", ErrBaseFeeUnsupported, err)
 		}
+		return nil, fmt.Errorf("failed to get base fee: %v", err)
+	}
+	return baseFee, nil
+}
 
-		chunk, err := caller.TryCall(opts, requireSuccess, chunk...)
-		if err != nil {
-			return calls, fmt.Errorf("call chunk [%d] failed: %v", i, err)
+// BlockHash gets the hash of given block by using the getBlockHash method of the multicall
+// contract. Since the EVM only knows the hashes of the 256 most recent blocks, excluding
+// the current block, the zero hash is returned for any other block instead of an error.
+func (caller *Caller) BlockHash(opts *bind.CallOpts, blockNumber uint64) (common.Hash, error) {
+	blockHash, err := caller.contract.GetBlockHash(opts, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get block hash: %v", err)
+	}
+	return blockHash, nil
+}
+
+// BlockTimestamp gets the current block timestamp by using the getCurrentBlockTimestamp
+// method of the multicall contract.
+func (caller *Caller) BlockTimestamp(opts *bind.CallOpts) (uint64, error) {
+	timestamp, err := caller.contract.GetCurrentBlockTimestamp(opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block timestamp: %v", err)
+	}
+	return timestamp.Uint64(), nil
+}
+
+// BlockGasLimit gets the current block gas limit by using the getCurrentBlockGasLimit
+// method of the multicall contract.
+func (caller *Caller) BlockGasLimit(opts *bind.CallOpts) (uint64, error) {
+	gasLimit, err := caller.contract.GetCurrentBlockGasLimit(opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block gas limit: %v", err)
+	}
+	return gasLimit.Uint64(), nil
+}
+
+// BlockCoinbase gets the current block coinbase by using the getCurrentBlockCoinbase
+// method of the multicall contract.
+func (caller *Caller) BlockCoinbase(opts *bind.CallOpts) (common.Address, error) {
+	coinbase, err := caller.contract.GetCurrentBlockCoinbase(opts)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get block coinbase: %v", err)
+	}
+	return coinbase, nil
+}
+
+// BlockDifficulty gets the current block difficulty by using the getCurrentBlockDifficulty
+// method of the multicall contract.
+func (caller *Caller) BlockDifficulty(opts *bind.CallOpts) (*big.Int, error) {
+	difficulty, err := caller.contract.GetCurrentBlockDifficulty(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block difficulty: %v", err)
+	}
+	return difficulty, nil
+}
+
+type blockNumberOutput struct {
+	BlockNumber *big.Int
+}
+
+// CallWithBlock makes multicalls like Call and also gets the block number with the
+// getBlockNumber method of the multicall contract in the same batch, so the block number
+// is the block of the results.
+func (caller *Caller) CallWithBlock(opts *bind.CallOpts, calls ...*Call) (uint64, []*Call, error) {
+	multicall, err := caller.multicallContract()
+	if err != nil {
+		return 0, calls, err
+	}
+	blockCall := multicall.NewCall(new(blockNumberOutput), "getBlockNumber")
+
+	allCalls, err := caller.Call(opts, append(calls[:len(calls):len(calls)], blockCall)...)
+	if blockCall.Failed || blockCall.UnpackErr != nil {
+		return 0, calls, fmt.Errorf("failed to get block number: %v", err)
+	}
+	if multiErr := (*MultiError)(nil); err != nil && !errors.As(err, &multiErr) {
+		return 0, calls, err
+	}
+	// the call errors are of the given calls since the block call is the last one
+	return blockCall.Outputs.(*blockNumberOutput).BlockNumber.Uint64(), allCalls[:len(calls)], err
+}
+
+type ethBalanceOutput struct {
+	Balance *big.Int
+}
+
+// EthBalances gets the ETH balances of given addresses by using the getEthBalance method
+// of the multicall contract. Duplicate addresses are queried once and the calls are chunked.
+func (caller *Caller) EthBalances(opts *bind.CallOpts, addrs ...common.Address) (map[common.Address]*big.Int, error) {
+	multicall, err := caller.multicallContract()
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []*Call
+	seen := make(map[common.Address]bool)
+	for _, addr := range addrs {
+		if seen[addr] {
+			continue
 		}
-		allCalls = append(allCalls, chunk...)
+		seen[addr] = true
+		calls = append(calls, multicall.NewCall(new(ethBalanceOutput), "getEthBalance", addr))
 	}
-	return allCalls, nil
+
+	calls, err = caller.CallChunked(opts, caller.helperChunkSize(), caller.cooldown, calls...)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[common.Address]*big.Int)
+	for _, call := range calls {
+		balances[call.Inputs[0].(common.Address)] = call.Outputs.(*ethBalanceOutput).Balance
+	}
+	return balances, nil
+}
+
+// multicallContract returns the multicall contract as a call factory.
+func (caller *Caller) multicallContract() (*Contract, error) {
+	parsedABI, err := contract_multicall.MulticallMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse multicall abi: %v", err)
+	}
+	return &Contract{
+		ABI:     parsedABI,
+		Address: caller.address,
+	}, nil
 }
@@ -1,12 +1,24 @@
 package multicall
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
 	"github.com/stretchr/testify/require"
 )
@@ -87,22 +99,132 @@ const testABI = `[
 	}
 ]`
 
+const (
+	testBlockNumber     = 123456
+	testChainID         = 137
+	testBlockTimestamp  = 1680000000
+	testBlockGasLimit   = 30000000
+	testBlockDifficulty = 2
+	testCoinbase        = "0x0000000000000000000000000000000000000001"
+	testBaseFee         = 30000000000
+)
+
+var testBlockHash = common.HexToHash("0x80ed808b586aeebe9cdd4088ea4dea0a8e322909c0e4493c993e060e89c09ed1")
+
+func toCall3(calls []contract_multicall.Multicall3Call) (calls3 []contract_multicall.Multicall3Call3) {
+	for _, call := range calls {
+		calls3 = append(calls3, contract_multicall.Multicall3Call3{
+			Target:   call.Target,
+			CallData: call.CallData,
+		})
+	}
+	return
+}
+
 type multicallStub struct {
-	returnData func(calls []contract_multicall.Multicall3Call3) [][]byte
+	returnData  func(calls []contract_multicall.Multicall3Call3) [][]byte
+	failures    map[int]bool
+	callErr     func(calls []contract_multicall.Multicall3Call3) error
+	checkOpts   func(opts *bind.CallOpts)
+	noBaseFee   bool
+	blockNumber func() uint64
 }
 
 func (ms *multicallStub) Aggregate3(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call3) (results []contract_multicall.Multicall3Result, err error) {
+	if ms.checkOpts != nil {
+		ms.checkOpts(opts)
+	}
+	if ms.callErr != nil {
+		if err := ms.callErr(calls); err != nil {
+			return nil, err
+		}
+	}
 	allReturnData := ms.returnData(calls)
-	for _, returnData := range allReturnData {
+	for i, returnData := range allReturnData {
 		results = append(results, contract_multicall.Multicall3Result{
-			Success:    true,
+			Success:    !ms.failures[i],
 			ReturnData: returnData,
 		})
 	}
 	return
 }
 
+func (ms *multicallStub) Aggregate(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call) (result struct {
+	BlockNumber *big.Int
+	ReturnData  [][]byte
+}, err error) {
+	result.BlockNumber = big.NewInt(testBlockNumber)
+	result.ReturnData = ms.returnData(toCall3(calls))
+	return
+}
+
+func (ms *multicallStub) BlockAndAggregate(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call) (result struct {
+	BlockNumber *big.Int
+	BlockHash   [32]byte
+	ReturnData  []contract_multicall.Multicall3Result
+}, err error) {
+	result.BlockNumber = big.NewInt(testBlockNumber)
+	result.BlockHash = testBlockHash
+	result.ReturnData, err = ms.Aggregate3(opts, toCall3(calls))
+	return
+}
+
+func (ms *multicallStub) TryBlockAndAggregate(opts *bind.CallOpts, requireSuccess bool, calls []contract_multicall.Multicall3Call) (result struct {
+	BlockNumber *big.Int
+	BlockHash   [32]byte
+	ReturnData  []contract_multicall.Multicall3Result
+}, err error) {
+	return ms.BlockAndAggregate(opts, calls)
+}
+
+func (ms *multicallStub) GetBasefee(opts *bind.CallOpts) (*big.Int, error) {
+	if ms.noBaseFee {
+		return nil, errors.New("execution reverted")
+	}
+	return big.NewInt(testBaseFee), nil
+}
+
+// GetBlockHash returns the test block hash for the 256 blocks before the test block
+// and the zero hash for the rest, like the EVM.
+func (ms *multicallStub) GetBlockHash(opts *bind.CallOpts, blockNumber *big.Int) ([32]byte, error) {
+	n := blockNumber.Int64()
+	if n >= testBlockNumber || n < testBlockNumber-256 {
+		return [32]byte{}, nil
+	}
+	return testBlockHash, nil
+}
+
+func (ms *multicallStub) GetBlockNumber(opts *bind.CallOpts) (*big.Int, error) {
+	if ms.blockNumber != nil {
+		return new(big.Int).SetUint64(ms.blockNumber()), nil
+	}
+	return big.NewInt(testBlockNumber), nil
+}
+
+func (ms *multicallStub) GetChainId(opts *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(testChainID), nil
+}
+
+func (ms *multicallStub) GetCurrentBlockCoinbase(opts *bind.CallOpts) (common.Address, error) {
+	return common.HexToAddress(testCoinbase), nil
+}
+
+func (ms *multicallStub) GetCurrentBlockDifficulty(opts *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(testBlockDifficulty), nil
+}
+
+func (ms *multicallStub) GetCurrentBlockGasLimit(opts *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(testBlockGasLimit), nil
+}
+
+func (ms *multicallStub) GetCurrentBlockTimestamp(opts *bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(testBlockTimestamp), nil
+}
+
 func (ms *multicallStub) TryAggregate(opts *bind.CallOpts, requireSuccess bool, calls []contract_multicall.Multicall3Call) ([]contract_multicall.Multicall3Result, error) {
+	if ms.returnData != nil {
+		return ms.Aggregate3(opts, toCall3(calls))
+	}
 	return []contract_multicall.Multicall3Result{
 		{
 			Success:    true,
@@ -171,6 +293,7 @@ func TestCaller_TwoCalls(t *testing.T) {
 	r.Equal(values1.Val4, call1Out.Val4)
 	r.Equal(values1.Val5, call1Out.Val5)
 	r.Equal(values1.Val6, call1Out.Val6)
+	r.NotEmpty(calls[0].RawReturn)
 
 	call2Out := calls[1].Outputs.(*testType)
 	r.Equal(values2.Val1, call2Out.Val1)
@@ -295,6 +418,11 @@ func TestCaller_BadOutput(t *testing.T) {
 	calls, err := caller.Call(nil, call)
 	r.Error(err)
 	r.Len(calls, 1)
+	r.Error(calls[0].UnpackErr)
+
+	calls, err = caller.Strict().Call(nil, call)
+	r.Error(err)
+	r.Len(calls, 1)
 }
 
 func TestCaller_WrongOutputsType(t *testing.T) {
@@ -323,10 +451,574 @@ func TestCaller_WrongOutputsType(t *testing.T) {
 
 	calls, err := caller.Call(nil, call)
 	r.Error(err)
+	r.Len(calls, 1)
+	r.ErrorContains(calls[0].UnpackErr, "not a struct")
+
+	calls, err = caller.Strict().Call(nil, call)
+	r.Error(err)
 	r.ErrorContains(err, "not a struct")
 	r.Len(calls, 1)
 }
 
+func TestCaller_UnpackErrorContinues(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(new([]struct{}), "testFunc", true)
+	call2 := testContract.NewCall(new(boolOutput), "testFunc", true)
+
+	caller := &Caller{contract: echoStub()}
+
+	calls, err := caller.Call(nil, call1, call2)
+	r.Error(err)
+	r.Error(calls[0].UnpackErr)
+	r.NoError(calls[1].UnpackErr)
+	r.True(calls[1].Outputs.(*boolOutput).Val1)
+}
+
+func TestCaller_UnpackErrorMethods(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	newCalls := func() []*Call {
+		return []*Call{
+			testContract.NewCall(new(boolOutput), "testFunc", true),
+			testContract.NewCall(new(struct{ Val1 string }), "testFunc", true), // wrong type
+		}
+	}
+
+	caller := &Caller{contract: echoStub()}
+	methods := map[string]func(calls []*Call) error{
+		"TryCall": func(calls []*Call) error {
+			_, err := caller.TryCall(nil, false, calls...)
+			return err
+		},
+		"TryCallChunked": func(calls []*Call) error {
+			_, err := caller.TryCallChunked(nil, false, 1, 0, calls...)
+			return err
+		},
+		"Aggregate": func(calls []*Call) error {
+			_, _, err := caller.Aggregate(nil, calls...)
+			return err
+		},
+		"BlockAndAggregate": func(calls []*Call) error {
+			_, _, _, err := caller.BlockAndAggregate(nil, calls...)
+			return err
+		},
+		"TryBlockAndAggregate": func(calls []*Call) error {
+			_, _, _, err := caller.TryBlockAndAggregate(nil, false, calls...)
+			return err
+		},
+	}
+	for name, method := range methods {
+		calls := newCalls()
+		err := method(calls)
+		var multiErr *MultiError
+		r.ErrorAs(err, &multiErr, name)
+		r.Len(multiErr.Errors, 1, name)
+		r.Equal(1, multiErr.Errors[0].Index, name)
+		r.True(calls[0].Outputs.(*boolOutput).Val1, name)
+		r.Error(calls[1].UnpackErr, name)
+	}
+}
+
+func TestCaller_Aggregate(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call := testContract.NewCall(
+		new(struct{ Val1 bool }), "testFunc",
+		true,
+	)
+
+	caller := &Caller{
+		contract: &multicallStub{
+			returnData: func(calls []contract_multicall.Multicall3Call3) [][]byte {
+				return [][]byte{
+					// return inputs as outputs by stripping the method prefix
+					calls[0].CallData[4:],
+				}
+			},
+		},
+	}
+
+	// the results of a previous batch are reset
+	call.Failed = true
+	call.UnpackErr = errors.New("stale unpack error")
+
+	blockNumber, calls, err := caller.Aggregate(nil, call)
+	r.NoError(err)
+	r.Equal(uint64(testBlockNumber), blockNumber)
+	r.Len(calls, 1)
+	r.True(calls[0].Outputs.(*struct{ Val1 bool }).Val1)
+	r.False(calls[0].Failed)
+	r.NoError(calls[0].UnpackErr)
+}
+
+func TestCaller_AggregateCanFail(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(new(struct{ Val1 bool }), "testFunc", true)
+	call2 := testContract.NewCall(new(struct{ Val1 bool }), "testFunc", true).AllowFailure()
+
+	caller := &Caller{contract: &multicallStub{}}
+
+	_, calls, err := caller.Aggregate(nil, call1, call2)
+	r.Error(err)
+	r.ErrorContains(err, "index [1]")
+	r.Len(calls, 2)
+}
+
+func TestCaller_BlockAndAggregate(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call := testContract.NewCall(
+		new(struct{ Val1 bool }), "testFunc",
+		true,
+	)
+
+	caller := &Caller{
+		contract: &multicallStub{
+			returnData: func(calls []contract_multicall.Multicall3Call3) [][]byte {
+				return [][]byte{
+					// return inputs as outputs by stripping the method prefix
+					calls[0].CallData[4:],
+				}
+			},
+		},
+	}
+
+	blockNumber, blockHash, calls, err := caller.BlockAndAggregate(nil, call)
+	r.NoError(err)
+	r.Equal(uint64(testBlockNumber), blockNumber)
+	r.Equal(testBlockHash, blockHash)
+	r.Len(calls, 1)
+	r.False(calls[0].Failed)
+	r.True(calls[0].Outputs.(*struct{ Val1 bool }).Val1)
+}
+
+func TestCaller_TryBlockAndAggregate(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(new(struct{ Val1 bool }), "testFunc", true)
+	call2 := testContract.NewCall(new(struct{ Val1 bool }), "testFunc", true)
+
+	caller := &Caller{
+		contract: &multicallStub{
+			returnData: func(calls []contract_multicall.Multicall3Call3) [][]byte {
+				return [][]byte{
+					calls[0].CallData[4:],
+					calls[1].CallData[4:],
+				}
+			},
+			failures: map[int]bool{1: true},
+		},
+	}
+
+	blockNumber, blockHash, calls, err := caller.TryBlockAndAggregate(nil, false, call1, call2)
+	r.NoError(err)
+	r.Equal(uint64(testBlockNumber), blockNumber)
+	r.Equal(testBlockHash, blockHash)
+	r.Len(calls, 2)
+	r.False(calls[0].Failed)
+	r.True(calls[1].Failed)
+}
+
+func TestCaller_EthBalances(t *testing.T) {
+	r := require.New(t)
+
+	addr1 := common.HexToAddress(testAddr1)
+	addr2 := common.HexToAddress(testAddr2)
+	balances := map[common.Address]*big.Int{
+		addr1: big.NewInt(100),
+		addr2: big.NewInt(200),
+	}
+
+	multicall, err := (&Caller{}).multicallContract()
+	r.NoError(err)
+
+	var callCount int
+	caller := &Caller{
+		contract: &multicallStub{
+			returnData: func(calls []contract_multicall.Multicall3Call3) (allReturnData [][]byte) {
+				for _, call := range calls {
+					callCount++
+					args, err := multicall.ABI.Methods["getEthBalance"].Inputs.Unpack(call.CallData[4:])
+					r.NoError(err)
+					returnData, err := multicall.ABI.Methods["getEthBalance"].Outputs.Pack(balances[args[0].(common.Address)])
+					r.NoError(err)
+					allReturnData = append(allReturnData, returnData)
+				}
+				return
+			},
+		},
+	}
+
+	result, err := caller.EthBalances(nil, addr1, addr2, addr1)
+	r.NoError(err)
+	r.Equal(2, callCount)
+	r.Equal(balances, result)
+}
+
+func TestCaller_ChainID(t *testing.T) {
+	r := require.New(t)
+
+	caller := &Caller{contract: &multicallStub{}}
+
+	chainID, err := caller.ChainID(nil)
+	r.NoError(err)
+	r.Equal(big.NewInt(testChainID), chainID)
+}
+
+func TestCaller_CallWithBlock(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	multicall, err := (&Caller{}).multicallContract()
+	r.NoError(err)
+	getBlockNumber := multicall.ABI.Methods["getBlockNumber"]
+
+	var sent int
+	stub := &multicallStub{
+		returnData: func(calls []contract_multicall.Multicall3Call3) (allReturnData [][]byte) {
+			sent = len(calls)
+			for _, call := range calls {
+				if bytes.Equal(call.CallData, getBlockNumber.ID) {
+					returnData, err := getBlockNumber.Outputs.Pack(big.NewInt(testBlockNumber))
+					r.NoError(err)
+					allReturnData = append(allReturnData, returnData)
+					continue
+				}
+				allReturnData = append(allReturnData, call.CallData[4:])
+			}
+			return
+		},
+		failures: map[int]bool{1: true},
+	}
+	caller := &Caller{contract: stub}
+
+	blockNumber, calls, err := caller.CallWithBlock(nil,
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure(),
+	)
+	r.Equal(3, sent)
+	r.Equal(uint64(testBlockNumber), blockNumber)
+	r.Len(calls, 2)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 1)
+	r.Equal(1, multiErr.Errors[0].Index)
+
+	// the block number call fails to unpack
+	_, _, err = (&Caller{contract: echoStub()}).CallWithBlock(nil, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorContains(err, "failed to get block number")
+}
+
+func TestCaller_BlockInfo(t *testing.T) {
+	r := require.New(t)
+
+	caller := &Caller{contract: &multicallStub{}}
+
+	blockNumber, err := caller.BlockNumber(nil)
+	r.NoError(err)
+	r.Equal(uint64(testBlockNumber), blockNumber)
+
+	blockHash, err := caller.BlockHash(nil, testBlockNumber-1)
+	r.NoError(err)
+	r.Equal(testBlockHash, blockHash)
+
+	// outside of the window of the last 256 blocks
+	blockHash, err = caller.BlockHash(nil, testBlockNumber-257)
+	r.NoError(err)
+	r.Equal(common.Hash{}, blockHash)
+
+	baseFee, err := caller.BaseFee(nil)
+	r.NoError(err)
+	r.Equal(big.NewInt(testBaseFee), baseFee)
+
+	timestamp, err := caller.BlockTimestamp(nil)
+	r.NoError(err)
+	r.Equal(uint64(testBlockTimestamp), timestamp)
+
+	gasLimit, err := caller.BlockGasLimit(nil)
+	r.NoError(err)
+	r.Equal(uint64(testBlockGasLimit), gasLimit)
+
+	coinbase, err := caller.BlockCoinbase(nil)
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testCoinbase), coinbase)
+
+	difficulty, err := caller.BlockDifficulty(nil)
+	r.NoError(err)
+	r.Equal(big.NewInt(testBlockDifficulty), difficulty)
+}
+
+func TestCaller_BaseFeeUnsupported(t *testing.T) {
+	r := require.New(t)
+
+	caller := &Caller{contract: &multicallStub{noBaseFee: true}}
+	_, err := caller.BaseFee(nil)
+	r.ErrorIs(err, ErrBaseFeeUnsupported)
+
+	r.False(isRevert(errors.New("connection refused")))
+	r.True(isRevert(errors.New("invalid opcode: opcode 0x48 not defined")))
+}
+
+// echoStub returns the inputs of each call as its outputs.
+func echoStub() *multicallStub {
+	return &multicallStub{
+		returnData: func(calls []contract_multicall.Multicall3Call3) (allReturnData [][]byte) {
+			for _, call := range calls {
+				allReturnData = append(allReturnData, call.CallData[4:])
+			}
+			return
+		},
+	}
+}
+
+type boolOutput struct {
+	Val1 bool
+}
+
+func TestCaller_ConcurrentUse(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	caller := &Caller{
+		contract:  echoStub(),
+		logger:    &testLogger{},
+		chunkHook: &testChunkHook{},
+		limiter:   &testLimiter{},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			calls := []*Call{
+				testContract.NewCall(new(boolOutput), "testFunc", i%2 == 0),
+				testContract.NewCall(new(boolOutput), "testFunc", i%2 != 0),
+			}
+			if _, err := caller.Call(nil, calls...); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := caller.CallChunked(nil, 1, 0, calls...); err != nil {
+				errs <- err
+				return
+			}
+			if calls[0].Outputs.(*boolOutput).Val1 != (i%2 == 0) {
+				errs <- fmt.Errorf("unexpected output of call [%d]", i)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		r.NoError(err)
+	}
+}
+
+func TestCaller_CallConcurrent(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 50; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", i%3 == 0))
+	}
+
+	caller := &Caller{contract: echoStub()}
+
+	results, err := caller.CallConcurrent(nil, 3, 4, calls...)
+	r.NoError(err)
+	r.Len(results, 50)
+	for i, result := range results {
+		r.Equal(i%3 == 0, result.Outputs.(*boolOutput).Val1)
+	}
+}
+
+func TestCaller_CallConcurrentError(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 50; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc failure")
+	}
+	caller := &Caller{contract: stub}
+
+	_, err = caller.CallConcurrent(nil, 5, 4, calls...)
+	r.Error(err)
+	r.ErrorContains(err, "rpc failure")
+}
+
+func TestCaller_CallChunkedContextCancel(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		cancel() // cancel after the first chunk
+		return nil
+	}
+	caller := &Caller{contract: stub}
+
+	start := time.Now()
+	results, err := caller.CallChunkedContext(ctx, nil, 2, time.Hour, calls...)
+	r.ErrorIs(err, context.Canceled)
+	r.Less(time.Since(start), time.Minute)
+	r.Len(results, 2)
+	r.True(results[0].Outputs.(*boolOutput).Val1)
+}
+
+func TestCaller_ChunkSizeValidation(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+	hook := &testChunkHook{}
+	caller := &Caller{contract: echoStub(), chunkHook: hook}
+
+	for _, chunkSize := range []int{0, -1} {
+		_, err = caller.CallChunked(nil, chunkSize, 0, calls...)
+		r.ErrorIs(err, ErrInvalidChunkSize)
+		_, err = caller.CallChunkedContext(context.Background(), nil, chunkSize, 0, calls...)
+		r.ErrorIs(err, ErrInvalidChunkSize)
+		_, err = caller.TryCallChunked(nil, true, chunkSize, 0, calls...)
+		r.ErrorIs(err, ErrInvalidChunkSize)
+		_, err = caller.CallChunkedRetry(nil, chunkSize, 0, 1, calls...)
+		r.ErrorIs(err, ErrInvalidChunkSize)
+		_, err = caller.CallConcurrent(nil, chunkSize, 2, calls...)
+		r.ErrorIs(err, ErrInvalidChunkSize)
+		_, err = caller.CallStream(context.Background(), nil, chunkSize, calls...)
+		r.ErrorIs(err, ErrInvalidChunkSize)
+	}
+	_, err = caller.CallChunked(nil, 0, 0, calls...)
+	r.EqualError(err, "invalid chunk size: 0, must be positive")
+	r.Empty(hook.chunks)
+
+	// an oversized chunk size makes a single chunk
+	results, err := caller.CallChunked(nil, 10, 0, calls...)
+	r.NoError(err)
+	r.Len(results, 3)
+	r.Equal([]chunkDone{{index: 0, size: 3}}, hook.chunks)
+}
+
+func TestCaller_TryCallChunkedContextCancel(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		cancel() // cancel after the first chunk
+		return nil
+	}
+	var usedCtx context.Context
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		usedCtx = opts.Context
+	}
+	caller := &Caller{contract: stub}
+
+	start := time.Now()
+	results, err := caller.TryCallChunkedContext(ctx, nil, true, 2, time.Hour, calls...)
+	r.ErrorIs(err, context.Canceled)
+	r.Less(time.Since(start), time.Minute)
+	r.Len(results, 2)
+	r.True(results[0].Outputs.(*boolOutput).Val1)
+	r.ErrorIs(usedCtx.Err(), context.Canceled)
+}
+
+type transactorStub struct {
+	opts  *bind.TransactOpts
+	calls []contract_multicall.Multicall3Call3Value
+}
+
+func (ts *transactorStub) Aggregate3Value(opts *bind.TransactOpts, calls []contract_multicall.Multicall3Call3Value) (*types.Transaction, error) {
+	ts.opts = opts
+	ts.calls = calls
+	return types.NewTx(&types.LegacyTx{Value: opts.Value}), nil
+}
+
+func TestCaller_CallValue(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(nil, "testFunc", true).WithValue(big.NewInt(100))
+	call2 := testContract.NewCall(nil, "testFunc", false).AllowFailure()
+	call3 := testContract.NewCall(nil, "testFunc", true).WithValue(big.NewInt(23))
+
+	transactor := &transactorStub{}
+	caller := &Caller{transactor: transactor}
+
+	tx, err := caller.CallValue(&bind.TransactOpts{}, call1, call2, call3)
+	r.NoError(err)
+	r.Equal(big.NewInt(123), tx.Value())
+	r.Len(transactor.calls, 3)
+	r.Equal(big.NewInt(0), transactor.calls[1].Value)
+	r.True(transactor.calls[1].AllowFailure)
+
+	_, err = caller.CallValue(&bind.TransactOpts{Value: big.NewInt(1)}, call1, call2, call3)
+	r.Error(err)
+	r.ErrorContains(err, "does not match")
+
+	_, err = (&Caller{}).CallValue(&bind.TransactOpts{}, call1)
+	r.Error(err)
+}
+
 func TestDial(t *testing.T) {
 	r := require.New(t)
 
@@ -335,7 +1027,300 @@ func TestDial(t *testing.T) {
 	r.NotNil(caller)
 }
 
-func TestChunkInputs(t *testing.T) {
+// backendStub is a contract backend which makes the calls with the client stub. The
+// transactor and filterer methods are not implemented.
+type backendStub struct {
+	*clientStub
+	bind.ContractTransactor
+	bind.ContractFilterer
+}
+
+func TestNewBackend(t *testing.T) {
+	r := require.New(t)
+
+	backend := &backendStub{clientStub: &clientStub{}}
+	caller, err := NewBackend(backend, WithAddress(testAddr1))
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr1), caller.Address())
+	r.Same(backend, caller.client)
+	r.NotNil(caller.transactor)
+}
+
+func TestDialWithHeaders(t *testing.T) {
+	r := require.New(t)
+
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", ethService{}))
+	defer server.Stop()
+
+	var apiKeys []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		apiKeys = append(apiKeys, req.Header.Get("X-Api-Key"))
+		server.ServeHTTP(w, req)
+	}))
+	defer httpServer.Close()
+
+	caller, err := DialWithHeaders(context.Background(), httpServer.URL, map[string]string{"x-api-key": "secret"})
+	r.NoError(err)
+	chainID, err := caller.client.(chainIDReader).ChainID(context.Background())
+	r.NoError(err)
+	r.Equal(big.NewInt(testChainID), chainID)
+	r.Equal([]string{"secret"}, apiKeys)
+}
+
+func TestCaller_Close(t *testing.T) {
+	r := require.New(t)
+
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", ethService{}))
+	defer server.Stop()
+	wsServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer wsServer.Close()
+
+	caller, err := Dial(context.Background(), "ws"+strings.TrimPrefix(wsServer.URL, "http"))
+	r.NoError(err)
+	strictCaller := caller.Strict()
+	caller.Close()
+	strictCaller.Close() // idempotent
+	_, err = caller.client.(chainIDReader).ChainID(context.Background())
+	r.ErrorIs(err, rpc.ErrClientQuit)
+
+	// the client given by the user is not closed
+	rpcClient := rpc.DialInProc(server)
+	caller, err = NewFromRPC(rpcClient, WithAddress(testAddr1))
+	r.NoError(err)
+	caller.Close()
+	var chainID hexutil.Big
+	r.NoError(rpcClient.Call(&chainID, "eth_chainId"))
+	(&Caller{}).Close()
+}
+
+func TestNewFromRPC(t *testing.T) {
+	r := require.New(t)
+
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", ethService{}))
+	defer server.Stop()
+	rpcClient := rpc.DialInProc(server)
+
+	caller, err := NewFromRPC(rpcClient, WithAddress(testAddr1))
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr1), caller.Address())
+	r.Same(rpcClient, caller.rpc)
+
+	chainID, err := caller.client.(chainIDReader).ChainID(context.Background())
+	r.NoError(err)
+	r.Equal(big.NewInt(testChainID), chainID)
+}
+
+func TestChainDefaults(t *testing.T) {
+	r := require.New(t)
+
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", ethService{}))
+	defer server.Stop()
+	rpcClient := rpc.DialInProc(server)
+
+	ChainDefaults[testChainID] = testAddr2
+	defer delete(ChainDefaults, testChainID)
+
+	caller, err := NewFromRPC(rpcClient)
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr2), caller.Address())
+
+	// the given address is preferred
+	caller, err = NewFromRPC(rpcClient, WithAddress(testAddr1))
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr1), caller.Address())
+
+	// other chains use the default address
+	delete(ChainDefaults, testChainID)
+	ChainDefaults[testChainID+1] = testAddr2
+	defer delete(ChainDefaults, testChainID+1)
+	caller, err = NewFromRPC(rpcClient)
+	r.NoError(err)
+	r.Equal(common.HexToAddress(DefaultAddress), caller.Address())
+}
+
+func TestCaller_CallNamed(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	caller := &Caller{contract: echoStub()}
+
+	named, err := caller.CallNamed(nil,
+		testContract.NewCall(new(boolOutput), "testFunc", true).Name("yes"),
+		testContract.NewCall(new(boolOutput), "testFunc", false).Name("no"),
+	)
+	r.NoError(err)
+	r.Len(named, 2)
+	r.True(named["yes"].Outputs.(*boolOutput).Val1)
+	r.False(named["no"].Outputs.(*boolOutput).Val1)
+
+	_, err = caller.CallNamed(nil,
+		testContract.NewCall(new(boolOutput), "testFunc", true).Name("same"),
+		testContract.NewCall(new(boolOutput), "testFunc", false).Name("same"),
+	)
+	r.ErrorContains(err, "duplicate name")
+
+	_, err = caller.CallNamed(nil, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorContains(err, "no name")
+}
+
+func TestCaller_RawCall(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	data, err := testContract.ABI.Pack("testFunc", true)
+	r.NoError(err)
+
+	caller := &Caller{contract: echoStub()}
+
+	calls, err := caller.Call(nil, NewRawCall(common.HexToAddress(testAddr1), data, false))
+	r.NoError(err)
+	r.Equal(data[4:], calls[0].RawReturn)
+}
+
+func TestCaller_CallAt(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		r.NotNil(opts.Context)
+		r.Equal(big.NewInt(testBlockNumber), opts.BlockNumber)
+	}
+	caller := &Caller{contract: stub}
+
+	calls, err := caller.CallAt(big.NewInt(testBlockNumber), testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.NoError(err)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+}
+
+// slowClient blocks the calls until their context is done.
+type slowClient struct {
+	clientStub
+}
+
+func (sc *slowClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestCaller_CallContextCancelled(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	caller, err := New(&slowClient{})
+	r.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, err = caller.Call(&bind.CallOpts{Context: ctx}, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorIs(err, context.Canceled)
+	r.Less(time.Since(start), time.Second)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = caller.CallChunked(&bind.CallOpts{Context: ctx}, 1, 0, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorIs(err, context.DeadlineExceeded)
+}
+
+func TestCaller_MaxCallsPerAggregate(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	caller := NewWithContract(echoStub(), WithMaxCallsPerAggregate(4))
+	_, err = caller.Call(nil, calls...)
+	r.ErrorIs(err, ErrTooManyCalls)
+	r.ErrorContains(err, "use CallChunked")
+
+	_, err = caller.Call(nil, calls[:4]...)
+	r.NoError(err)
+	_, err = caller.CallChunked(nil, 4, 0, calls...)
+	r.NoError(err)
+
+	caller = NewWithContract(echoStub(), WithMaxCallsPerAggregate(-1))
+	_, err = caller.Call(nil, calls...)
+	r.NoError(err)
+}
+
+func TestCaller_CallPending(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		r.NotNil(opts.Context)
+		r.True(opts.Pending)
+		r.Nil(opts.BlockNumber)
+	}
+	caller := &Caller{contract: stub}
+
+	calls, err := caller.CallPending(testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.NoError(err)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+}
+
+func TestCaller_Address(t *testing.T) {
+	r := require.New(t)
+
+	caller, err := New(nil)
+	r.NoError(err)
+	r.Equal(common.HexToAddress(DefaultAddress), caller.Address())
+
+	caller, err = New(nil, WithAddress(testAddr1))
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr1), caller.Address())
+}
+
+func TestCaller_Contract(t *testing.T) {
+	r := require.New(t)
+
+	caller, err := New(&clientStub{})
+	r.NoError(err)
+	_, ok := caller.Contract().(*contract_multicall.MulticallCaller)
+	r.True(ok)
+
+	stub := echoStub()
+	r.Same(stub, NewWithContract(stub).Contract())
+}
+
+func TestCaller_WithAddress(t *testing.T) {
+	r := require.New(t)
+
+	client := &clientStub{}
+	caller, err := New(client, WithStrict())
+	r.NoError(err)
+
+	addrCaller, err := caller.WithAddress(common.HexToAddress(testAddr2))
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr2), addrCaller.Address())
+	r.Equal(common.HexToAddress(DefaultAddress), caller.Address())
+	r.True(addrCaller.strict)
+	r.Same(client, addrCaller.client.(*clientStub))
+	r.NotSame(caller.contract, addrCaller.contract)
+}
+
+func TestChunkSlice(t *testing.T) {
 	testCases := []struct {
 		name      string
 		chunkSize int
@@ -384,7 +1369,7 @@ func TestChunkInputs(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			r := require.New(t)
 
-			r.Equal(testCase.expected, chunkInputs(testCase.chunkSize, testCase.inputs))
+			r.Equal(testCase.expected, ChunkSlice(testCase.chunkSize, testCase.inputs))
 		})
 	}
 }
@@ -0,0 +1,148 @@
+package multicall
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+)
+
+// defaultGasEstimate is the gas estimate used for the calls which have no GasEstimate set
+// unless another estimate is set with WithDefaultGasEstimate.
+const defaultGasEstimate = 100000
+
+// gasEstimate returns the gas estimate used for the calls which have no GasEstimate set.
+func (caller *Caller) gasEstimate() uint64 {
+	if caller.defaultGas == 0 {
+		return defaultGasEstimate
+	}
+	return caller.defaultGas
+}
+
+// CallGasLimited makes multiple multicalls by chunking given calls so that the total gas
+// estimate of a chunk does not exceed maxGasPerChunk. A call which alone exceeds the limit
+// is sent in a chunk of its own.
+func (caller *Caller) CallGasLimited(opts *bind.CallOpts, maxGasPerChunk uint64, calls ...*Call) ([]*Call, error) {
+	ctx, _ := caller.callContext(opts)
+	return caller.callChunks(ctx, opts, calls, chunkByGas(caller.log(), maxGasPerChunk, caller.gasEstimate(), calls), 0, func(chunkOpts *bind.CallOpts, chunk []*Call) ([]*Call, error) {
+		chunkOpts, cancel := caller.withChunkTimeout(chunkOpts)
+		defer cancel()
+		return caller.Call(chunkOpts, chunk...)
+	})
+}
+
+func chunkByGas(log Logger, maxGasPerChunk, defaultGas uint64, calls []*Call) (chunks [][]*Call) {
+	var (
+		start int
+		total uint64
+	)
+	for i, call := range calls {
+		gas := call.GasEstimate
+		if gas == 0 {
+			gas = defaultGas
+		}
+
+		if gas > maxGasPerChunk {
+			log.Warnf("multicall: gas estimate %d of call at index [%d] exceeds %d, sending alone", gas, i, maxGasPerChunk)
+			if i > start {
+				chunks = append(chunks, calls[start:i])
+			}
+			chunks = append(chunks, calls[i:i+1])
+			start, total = i+1, 0
+			continue
+		}
+
+		if total+gas > maxGasPerChunk && i > start {
+			chunks = append(chunks, calls[start:i])
+			start, total = i, 0
+		}
+		total += gas
+	}
+	if start < len(calls) {
+		chunks = append(chunks, calls[start:])
+	}
+	return
+}
+
+const (
+	// aggregate3Overhead is the size of the selector, the array offset and the array length.
+	aggregate3Overhead = 4 + 32 + 32
+	// call3Overhead is the size of the tuple offset, target, allowFailure, data offset
+	// and data length words.
+	call3Overhead = 5 * 32
+)
+
+// CallByteLimited makes multiple multicalls by chunking given calls so that the calldata
+// of a chunk does not exceed maxBytesPerChunk. The calls are packed to measure them and
+// the chunks are made like with CallChunked. A call which alone exceeds the limit is sent
+// in a chunk of its own and a warning is logged.
+func (caller *Caller) CallByteLimited(opts *bind.CallOpts, maxBytesPerChunk int, calls ...*Call) ([]*Call, error) {
+	packedCalls, multiCalls, _, err := caller.pack(calls)
+	if err != nil {
+		return calls, err
+	}
+
+	ctx, _ := caller.callContext(opts)
+	return caller.callChunks(ctx, opts, calls, chunkCalls(calls, packedCalls, chunkByBytes(caller.log(), maxBytesPerChunk, multiCalls)), 0, func(chunkOpts *bind.CallOpts, chunk []*Call) ([]*Call, error) {
+		chunkOpts, cancel := caller.withChunkTimeout(chunkOpts)
+		defer cancel()
+		return caller.Call(chunkOpts, chunk...)
+	})
+}
+
+// chunkCalls turns the bounds of the chunks of the packed calls into the chunks of the
+// given calls. The calls which failed to pack go with the chunk of the next packed call,
+// or the last chunk, so their errors are recorded when the chunk is made.
+func chunkCalls(calls, packedCalls []*Call, bounds [][2]int) (chunks [][]*Call) {
+	if len(bounds) == 0 {
+		if len(calls) > 0 {
+			chunks = append(chunks, calls)
+		}
+		return
+	}
+	positions := callIndexes(calls, packedCalls)
+	start := 0
+	for i, bound := range bounds {
+		end := len(calls)
+		if i < len(bounds)-1 {
+			end = positions[bound[1]-1] + 1
+		}
+		chunks = append(chunks, calls[start:end])
+		start = end
+	}
+	return
+}
+
+// callIndexes returns the indexes of the chunk calls in the given calls.
+func callIndexes(calls, chunk []*Call) []int {
+	indexes := make([]int, 0, len(chunk))
+	for i, j := 0, 0; i < len(calls) && j < len(chunk); i++ {
+		if calls[i] == chunk[j] {
+			indexes = append(indexes, i)
+			j++
+		}
+	}
+	return indexes
+}
+
+// chunkByBytes returns the start and end indexes of the chunks.
+func chunkByBytes(log Logger, maxBytesPerChunk int, multiCalls []contract_multicall.Multicall3Call3) (chunks [][2]int) {
+	var (
+		start int
+		total = aggregate3Overhead
+	)
+	for i, multiCall := range multiCalls {
+		size := call3Overhead + (len(multiCall.CallData)+31)/32*32
+
+		if total+size > maxBytesPerChunk && i > start {
+			chunks = append(chunks, [2]int{start, i})
+			start, total = i, aggregate3Overhead
+		}
+		if total+size > maxBytesPerChunk {
+			log.Warnf("multicall: calldata size %d of call at index [%d] exceeds %d, sending alone", size, i, maxBytesPerChunk)
+		}
+		total += size
+	}
+	if start < len(multiCalls) {
+		chunks = append(chunks, [2]int{start, len(multiCalls)})
+	}
+	return
+}
@@ -0,0 +1,214 @@
+package multicall
+
+import (
+	"testing"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkByGas(t *testing.T) {
+	gasCalls := func(estimates ...uint64) (calls []*Call) {
+		for _, estimate := range estimates {
+			calls = append(calls, (&Call{}).WithGasEstimate(estimate))
+		}
+		return
+	}
+
+	testCases := []struct {
+		name     string
+		maxGas   uint64
+		gas      uint64
+		calls    []*Call
+		expected []int
+	}{
+		{
+			name:     "no calls",
+			maxGas:   100,
+			calls:    nil,
+			expected: nil,
+		},
+		{
+			name:     "all fit",
+			maxGas:   100,
+			calls:    gasCalls(10, 20, 30),
+			expected: []int{3},
+		},
+		{
+			name:     "greedy split",
+			maxGas:   100,
+			calls:    gasCalls(60, 30, 20, 50, 50),
+			expected: []int{2, 2, 1},
+		},
+		{
+			name:     "oversized call alone",
+			maxGas:   100,
+			calls:    gasCalls(10, 150, 20),
+			expected: []int{1, 1, 1},
+		},
+		{
+			name:     "default estimate",
+			maxGas:   100,
+			gas:      50,
+			calls:    gasCalls(0, 0, 0),
+			expected: []int{2, 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			var sizes []int
+			for _, chunk := range chunkByGas(nopLogger{}, testCase.maxGas, testCase.gas, testCase.calls) {
+				sizes = append(sizes, len(chunk))
+			}
+			r.Equal(testCase.expected, sizes)
+		})
+	}
+}
+
+func TestCaller_CallGasLimited(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", i%2 == 0).WithGasEstimate(40))
+	}
+
+	var chunkCount int
+	stub := echoStub()
+	returnData := stub.returnData
+	stub.returnData = func(calls []contract_multicall.Multicall3Call3) [][]byte {
+		chunkCount++
+		return returnData(calls)
+	}
+	caller := &Caller{contract: stub}
+
+	results, err := caller.CallGasLimited(nil, 100, calls...)
+	r.NoError(err)
+	r.Equal(3, chunkCount)
+	r.Len(results, 5)
+	for i, result := range results {
+		r.Equal(i%2 == 0, result.Outputs.(*boolOutput).Val1)
+	}
+
+	// the calls with no estimate use the default estimate of the caller
+	for _, call := range calls {
+		call.GasEstimate = 0
+	}
+	chunkCount = 0
+	_, err = caller.CallGasLimited(nil, 200000, calls...)
+	r.NoError(err)
+	r.Equal(3, chunkCount)
+
+	chunkCount = 0
+	WithDefaultGasEstimate(50)(caller)
+	_, err = caller.CallGasLimited(nil, 100, calls...)
+	r.NoError(err)
+	r.Equal(3, chunkCount)
+
+	chunkCount = 0
+	WithDefaultGasEstimate(20)(caller)
+	_, err = caller.CallGasLimited(nil, 100, calls...)
+	r.NoError(err)
+	r.Equal(1, chunkCount)
+}
+
+func TestChunkByBytes(t *testing.T) {
+	byteCalls := func(sizes ...int) (multiCalls []contract_multicall.Multicall3Call3) {
+		for _, size := range sizes {
+			multiCalls = append(multiCalls, contract_multicall.Multicall3Call3{CallData: make([]byte, size)})
+		}
+		return
+	}
+
+	testCases := []struct {
+		name     string
+		maxBytes int
+		calls    []contract_multicall.Multicall3Call3
+		expected [][2]int
+	}{
+		{
+			name:     "no calls",
+			maxBytes: 1000,
+			calls:    nil,
+			expected: nil,
+		},
+		{
+			name:     "all fit",
+			maxBytes: 1000,
+			calls:    byteCalls(4, 36, 68), // 192 + 224 + 256 + 68 overhead
+			expected: [][2]int{{0, 3}},
+		},
+		{
+			name:     "split",
+			maxBytes: 520,
+			calls:    byteCalls(4, 36, 68, 4), // 192, 224, 256, 192
+			expected: [][2]int{{0, 2}, {2, 4}},
+		},
+		{
+			name:     "oversized call alone",
+			maxBytes: 300,
+			calls:    byteCalls(4, 1000, 4),
+			expected: [][2]int{{0, 1}, {1, 2}, {2, 3}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := require.New(t)
+
+			r.Equal(testCase.expected, chunkByBytes(nopLogger{}, testCase.maxBytes, testCase.calls))
+		})
+	}
+}
+
+func TestCaller_CallByteLimited(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", i%2 == 0))
+	}
+	calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", 'a')) // bad input
+
+	var chunkCount int
+	stub := echoStub()
+	returnData := stub.returnData
+	stub.returnData = func(calls []contract_multicall.Multicall3Call3) [][]byte {
+		chunkCount++
+		return returnData(calls)
+	}
+	var progress [][2]int
+	caller := &Caller{contract: stub, progress: func(completed, total int) {
+		progress = append(progress, [2]int{completed, total})
+	}}
+
+	// each call is 36 bytes of calldata and takes 224 bytes in the batch
+	results, err := caller.CallByteLimited(nil, aggregate3Overhead+2*224, calls...)
+	r.Error(err)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 1)
+	r.Equal(5, multiErr.Errors[0].Index)
+
+	r.Equal(3, chunkCount)
+	r.Equal([][2]int{{2, 6}, {4, 6}, {6, 6}}, progress)
+	r.Len(results, 6)
+	for i, result := range results[:5] {
+		r.Equal(i%2 == 0, result.Outputs.(*boolOutput).Val1)
+	}
+	// the chunks are made with Call and follow the options of the caller
+	chunkCount = 0
+	WithMaxCallsPerAggregate(1)(caller)
+	_, err = caller.CallByteLimited(nil, aggregate3Overhead+2*224, calls[:4]...)
+	r.ErrorContains(err, "too many calls")
+	r.Zero(chunkCount)
+}
@@ -0,0 +1,52 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCircuitOpen is matched by the error of the chunked methods which stopped early since
+// too many chunks failed in a row, see WithCircuitBreaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitOpenError is the error of the last failed chunk when the circuit breaker opens.
+// It also carries the call errors of the chunks made before, including the earlier failed
+// chunks.
+type circuitOpenError struct {
+	failures int
+	err      *ChunkError
+	multiErr *MultiError
+}
+
+// Error implements the error interface.
+func (err *circuitOpenError) Error() string {
+	return fmt.Sprintf("%v after %d failed chunks in a row: %v", ErrCircuitOpen, err.failures, err.err)
+}
+
+// Unwrap returns the error of the last failed chunk.
+func (err *circuitOpenError) Unwrap() error {
+	return err.err
+}
+
+// Is reports whether the target is ErrCircuitOpen.
+func (err *circuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// As sets the target to the call errors of the chunks when it is a **MultiError.
+func (err *circuitOpenError) As(target any) bool {
+	multiErr, ok := target.(**MultiError)
+	if ok {
+		*multiErr = err.multiErr
+	}
+	return ok
+}
+
+// failChunk adds the chunk error to the errors of the calls of a failed chunk, so that the
+// chunked methods can continue with the next chunk. The calls are not marked failed since
+// they did not fail on chain and have no revert data.
+func failChunk(multiErr *MultiError, offset int, chunk []*Call, err error) {
+	for i, call := range chunk {
+		multiErr.add(offset+i, call, err)
+	}
+}
@@ -0,0 +1,82 @@
+package multicall
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_CircuitBreaker(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	newCalls := func() []*Call {
+		var calls []*Call
+		for i := 0; i < 6; i++ {
+			calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+		}
+		return calls
+	}
+	failingChunks := func(failing ...int) *multicallStub {
+		stub := echoStub()
+		var chunk int
+		stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+			defer func() { chunk++ }()
+			for _, i := range failing {
+				if i == chunk {
+					return errors.New("endpoint is down")
+				}
+			}
+			return nil
+		}
+		return stub
+	}
+
+	// the first failed chunk stops by default
+	caller := &Caller{contract: failingChunks(1)}
+	results, err := caller.CallChunked(nil, 1, 0, newCalls()...)
+	r.ErrorContains(err, "call chunk [1] failed")
+	r.NotErrorIs(err, ErrCircuitOpen)
+	r.Len(results, 1)
+
+	// single failures are skipped
+	caller = &Caller{contract: failingChunks(1, 3), maxFailures: 2}
+	results, err = caller.CallChunked(nil, 1, 0, newCalls()...)
+	r.Len(results, 6)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 2)
+	r.Equal(1, multiErr.Errors[0].Index)
+	r.Equal(3, multiErr.Errors[1].Index)
+	var chunkErr *ChunkError
+	r.ErrorAs(multiErr.Errors[1], &chunkErr)
+	r.Equal([]int{3}, chunkErr.Indexes)
+	// the calls of a failed chunk did not fail on chain
+	r.False(results[1].Failed)
+	r.Nil(results[1].RawReturn)
+	r.False(results[2].Failed)
+	r.True(results[2].Outputs.(*boolOutput).Val1)
+
+	// consecutive failures open the circuit
+	option := WithCircuitBreaker(2)
+	caller = &Caller{contract: failingChunks(1, 3, 4, 5)}
+	option(caller)
+	results, err = caller.CallChunked(nil, 1, 0, newCalls()...)
+	r.ErrorIs(err, ErrCircuitOpen)
+	r.ErrorAs(err, &chunkErr)
+	r.Equal(4, chunkErr.Chunk)
+	r.ErrorContains(err, "after 2 failed chunks in a row")
+	r.Len(results, 5)
+	r.False(results[4].Failed)
+	r.False(results[2].Failed)
+	// the errors of the earlier chunks are kept
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 3)
+	r.Equal(1, multiErr.Errors[0].Index)
+	r.Equal(3, multiErr.Errors[1].Index)
+	r.Equal(4, multiErr.Errors[2].Index)
+}
@@ -1,11 +1,42 @@
 package contract_multicall
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 // Interface is an abstraction of the contract.
 type Interface interface {
+	Aggregate(opts *bind.CallOpts, calls []Multicall3Call) (struct {
+		BlockNumber *big.Int
+		ReturnData  [][]byte
+	}, error)
 	Aggregate3(opts *bind.CallOpts, calls []Multicall3Call3) ([]Multicall3Result, error)
+	BlockAndAggregate(opts *bind.CallOpts, calls []Multicall3Call) (struct {
+		BlockNumber *big.Int
+		BlockHash   [32]byte
+		ReturnData  []Multicall3Result
+	}, error)
+	GetBasefee(opts *bind.CallOpts) (*big.Int, error)
+	GetBlockHash(opts *bind.CallOpts, blockNumber *big.Int) ([32]byte, error)
+	GetBlockNumber(opts *bind.CallOpts) (*big.Int, error)
+	GetChainId(opts *bind.CallOpts) (*big.Int, error)
+	GetCurrentBlockCoinbase(opts *bind.CallOpts) (common.Address, error)
+	GetCurrentBlockDifficulty(opts *bind.CallOpts) (*big.Int, error)
+	GetCurrentBlockGasLimit(opts *bind.CallOpts) (*big.Int, error)
+	GetCurrentBlockTimestamp(opts *bind.CallOpts) (*big.Int, error)
 	TryAggregate(opts *bind.CallOpts, requireSuccess bool, calls []Multicall3Call) ([]Multicall3Result, error)
+	TryBlockAndAggregate(opts *bind.CallOpts, requireSuccess bool, calls []Multicall3Call) (struct {
+		BlockNumber *big.Int
+		BlockHash   [32]byte
+		ReturnData  []Multicall3Result
+	}, error)
+}
+
+// TransactorInterface is an abstraction of the contract's paid methods.
+type TransactorInterface interface {
+	Aggregate3Value(opts *bind.TransactOpts, calls []Multicall3Call3Value) (*types.Transaction, error)
 }
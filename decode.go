@@ -0,0 +1,161 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// DecodeInto decodes the raw return data of the call into out, which must be a pointer.
+// A single output can be decoded into a scalar or, if it is a tuple, into a struct.
+// Multiple outputs are decoded into struct fields by the `abi` tags or, if the struct
+// has no such tags, by the field order. A tagged struct may have fields for only some of
+// the outputs, and the outputs without a tagged field are skipped.
+func (call *Call) DecodeInto(out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("decode target is not a non-nil pointer")
+	}
+	if call.Contract == nil || call.Contract.ABI == nil {
+		return errors.New("call has no abi to decode with")
+	}
+	method, ok := call.Contract.ABI.Methods[call.Method]
+	if !ok {
+		return fmt.Errorf("method '%s' not found in abi", call.Method)
+	}
+
+	values, err := method.Outputs.Unpack(call.RawReturn)
+	if err != nil {
+		return fmt.Errorf("failed to unpack '%s' outputs: %v", call.Method, err)
+	}
+
+	elem := v.Elem()
+	if len(values) == 1 && (elem.Kind() != reflect.Struct || method.Outputs[0].Type.T == abi.TupleTy) {
+		return assign(elem, values[0])
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode %d outputs into %s", len(values), elem.Type())
+	}
+
+	fields := fieldsByTag(elem)
+	if len(fields) == 0 && elem.NumField() < len(values) {
+		return fmt.Errorf("struct has %d fields but there are %d outputs", elem.NumField(), len(values))
+	}
+	for i, output := range method.Outputs {
+		var field reflect.Value
+		if len(fields) > 0 {
+			var ok bool
+			if field, ok = fields[output.Name]; !ok {
+				continue
+			}
+		} else if i < elem.NumField() {
+			field = elem.Field(i)
+		} else {
+			return fmt.Errorf("output '%s' has no matching field", output.Name)
+		}
+		if err := assign(field, values[i]); err != nil {
+			return fmt.Errorf("failed to decode output '%s': %v", output.Name, err)
+		}
+	}
+	return nil
+}
+
+// DecodeToMap decodes the raw return data of the call into a map which is keyed by the
+// output names. The unnamed outputs are keyed by their positions as arg0, arg1 and so on.
+// Tuples are decoded into nested maps in the same way, so no types are needed at compile
+// time.
+func (call *Call) DecodeToMap() (map[string]any, error) {
+	if call.Contract == nil || call.Contract.ABI == nil {
+		return nil, errors.New("call has no abi to decode with")
+	}
+	method, ok := call.Contract.ABI.Methods[call.Method]
+	if !ok {
+		return nil, fmt.Errorf("method '%s' not found in abi", call.Method)
+	}
+
+	values, err := method.Outputs.Unpack(call.RawReturn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack '%s' outputs: %v", call.Method, err)
+	}
+
+	decoded := make(map[string]any, len(values))
+	for i, output := range method.Outputs {
+		decoded[argName(output.Name, i)] = toMapValue(output.Type, reflect.ValueOf(values[i]))
+	}
+	return decoded, nil
+}
+
+// argName returns the name of the argument or its position if it has no name.
+func argName(name string, i int) string {
+	if name == "" {
+		return fmt.Sprintf("arg%d", i)
+	}
+	return name
+}
+
+// toMapValue converts the tuples in the decoded value into maps.
+func toMapValue(typ abi.Type, v reflect.Value) any {
+	switch typ.T {
+	case abi.TupleTy:
+		tuple := make(map[string]any, len(typ.TupleElems))
+		for i, elem := range typ.TupleElems {
+			tuple[argName(typ.TupleRawNames[i], i)] = toMapValue(*elem, v.Field(i))
+		}
+		return tuple
+	case abi.SliceTy, abi.ArrayTy:
+		if typ.Elem.T != abi.TupleTy {
+			return v.Interface()
+		}
+		elems := make([]any, v.Len())
+		for i := range elems {
+			elems[i] = toMapValue(*typ.Elem, v.Index(i))
+		}
+		return elems
+	default:
+		return v.Interface()
+	}
+}
+
+// fieldsByTag maps the `abi` tags of the struct fields to the fields.
+func fieldsByTag(v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < v.NumField(); i++ {
+		if tag, ok := v.Type().Field(i).Tag.Lookup("abi"); ok {
+			fields[tag] = v.Field(i)
+		}
+	}
+	return fields
+}
+
+// assign converts and sets the value without panicking on type mismatches.
+func assign(dst reflect.Value, value any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cannot assign %T to %s: %v", value, dst.Type(), r)
+		}
+	}()
+	if !dst.CanSet() {
+		return fmt.Errorf("cannot set %s", dst.Type())
+	}
+	converted := abi.ConvertType(value, reflect.New(dst.Type()).Interface())
+	dst.Set(reflect.ValueOf(converted).Elem())
+	return nil
+}
+
+// DecodeAll decodes the raw return data of each call into a T like DecodeInto. The
+// returned errors are parallel to the calls and the values of the calls which failed on
+// chain or failed to decode are left zero.
+func DecodeAll[T any](calls []*Call) ([]T, []error) {
+	values := make([]T, len(calls))
+	errs := make([]error, len(calls))
+	for i, call := range calls {
+		if call.Failed {
+			errs[i] = ErrCallFailed
+			continue
+		}
+		errs[i] = call.DecodeInto(&values[i])
+	}
+	return values, errs
+}
@@ -0,0 +1,233 @@
+package multicall
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const decodeABI = `[
+	{
+		"inputs":[],
+		"name":"balance",
+		"outputs":[
+			{
+				"name":"balance",
+				"type":"uint256"
+			}
+		],
+		"stateMutability":"view",
+		"type":"function"
+	},
+	{
+		"inputs":[],
+		"name":"state",
+		"outputs":[
+			{
+				"name":"owner",
+				"type":"address"
+			},
+			{
+				"name":"amount",
+				"type":"uint256"
+			}
+		],
+		"stateMutability":"view",
+		"type":"function"
+	},
+	{
+		"inputs":[],
+		"name":"info",
+		"outputs":[
+			{
+				"components":[
+					{
+						"name":"owner",
+						"type":"address"
+					},
+					{
+						"name":"amount",
+						"type":"uint256"
+					}
+				],
+				"name":"info",
+				"type":"tuple"
+			}
+		],
+		"stateMutability":"view",
+		"type":"function"
+	}
+]`
+
+type decodeState struct {
+	Owner  common.Address
+	Amount *big.Int
+}
+
+type decodeTaggedState struct {
+	Amount *big.Int       `abi:"amount"`
+	Owner  common.Address `abi:"owner"`
+}
+
+func TestCall_DecodeInto(t *testing.T) {
+	r := require.New(t)
+
+	contract, err := NewContract(decodeABI, testAddr1)
+	r.NoError(err)
+
+	owner := common.HexToAddress(testAddr2)
+	amount := big.NewInt(1234)
+
+	balanceCall := contract.NewCall(nil, "balance")
+	balanceCall.RawReturn, err = contract.ABI.Methods["balance"].Outputs.Pack(amount)
+	r.NoError(err)
+
+	var balance *big.Int
+	r.NoError(balanceCall.DecodeInto(&balance))
+	r.Equal(amount, balance)
+
+	var wrongType string
+	r.Error(balanceCall.DecodeInto(&wrongType))
+
+	stateCall := contract.NewCall(nil, "state")
+	stateCall.RawReturn, err = contract.ABI.Methods["state"].Outputs.Pack(owner, amount)
+	r.NoError(err)
+
+	var state decodeState
+	r.NoError(stateCall.DecodeInto(&state))
+	r.Equal(owner, state.Owner)
+	r.Equal(amount, state.Amount)
+
+	var taggedState decodeTaggedState
+	r.NoError(stateCall.DecodeInto(&taggedState))
+	r.Equal(owner, taggedState.Owner)
+	r.Equal(amount, taggedState.Amount)
+
+	var amountOnly struct {
+		Amount *big.Int `abi:"amount"`
+	}
+	r.NoError(stateCall.DecodeInto(&amountOnly))
+	r.Equal(amount, amountOnly.Amount)
+
+	var tooFewFields struct {
+		Owner common.Address
+	}
+	r.ErrorContains(stateCall.DecodeInto(&tooFewFields), "struct has 1 fields but there are 2 outputs")
+
+	infoCall := contract.NewCall(nil, "info")
+	infoCall.RawReturn, err = contract.ABI.Methods["info"].Outputs.Pack(struct {
+		Owner  common.Address
+		Amount *big.Int
+	}{owner, amount})
+	r.NoError(err)
+
+	var info decodeState
+	r.NoError(infoCall.DecodeInto(&info))
+	r.Equal(owner, info.Owner)
+	r.Equal(amount, info.Amount)
+
+	r.Error(infoCall.DecodeInto(info))
+}
+
+const unnamedOutputsABI = `[
+	{
+		"inputs":[],
+		"name":"pair",
+		"outputs":[
+			{
+				"name":"",
+				"type":"address"
+			},
+			{
+				"name":"",
+				"type":"uint256"
+			}
+		],
+		"stateMutability":"view",
+		"type":"function"
+	}
+]`
+
+func TestCall_DecodeToMap(t *testing.T) {
+	r := require.New(t)
+
+	contract, err := NewContract(decodeABI, testAddr1)
+	r.NoError(err)
+
+	owner := common.HexToAddress(testAddr2)
+	amount := big.NewInt(1234)
+
+	stateCall := contract.NewCall(nil, "state")
+	stateCall.RawReturn, err = contract.ABI.Methods["state"].Outputs.Pack(owner, amount)
+	r.NoError(err)
+	decoded, err := stateCall.DecodeToMap()
+	r.NoError(err)
+	r.Equal(map[string]any{"owner": owner, "amount": amount}, decoded)
+
+	var amountOnly struct {
+		Amount *big.Int `abi:"amount"`
+	}
+	r.NoError(stateCall.DecodeInto(&amountOnly))
+	r.Equal(amount, amountOnly.Amount)
+
+	var tooFewFields struct {
+		Owner common.Address
+	}
+	r.ErrorContains(stateCall.DecodeInto(&tooFewFields), "struct has 1 fields but there are 2 outputs")
+
+	infoCall := contract.NewCall(nil, "info")
+	infoCall.RawReturn, err = contract.ABI.Methods["info"].Outputs.Pack(struct {
+		Owner  common.Address
+		Amount *big.Int
+	}{owner, amount})
+	r.NoError(err)
+	decoded, err = infoCall.DecodeToMap()
+	r.NoError(err)
+	r.Equal(map[string]any{"info": map[string]any{"owner": owner, "amount": amount}}, decoded)
+
+	unnamedContract, err := NewContract(unnamedOutputsABI, testAddr1)
+	r.NoError(err)
+	pairCall := unnamedContract.NewCall(nil, "pair")
+	pairCall.RawReturn, err = unnamedContract.ABI.Methods["pair"].Outputs.Pack(owner, amount)
+	r.NoError(err)
+	decoded, err = pairCall.DecodeToMap()
+	r.NoError(err)
+	r.Equal(map[string]any{"arg0": owner, "arg1": amount}, decoded)
+
+	pairCall.RawReturn = []byte{0x01}
+	_, err = pairCall.DecodeToMap()
+	r.ErrorContains(err, "failed to unpack")
+
+	_, err = NewRawCall(owner, nil, false).DecodeToMap()
+	r.Error(err)
+}
+
+func TestDecodeAll(t *testing.T) {
+	r := require.New(t)
+
+	contract, err := NewContract(decodeABI, testAddr1)
+	r.NoError(err)
+
+	owner := common.HexToAddress(testAddr2)
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		call := contract.NewCall(nil, "state")
+		call.RawReturn, err = contract.ABI.Methods["state"].Outputs.Pack(owner, big.NewInt(int64(i+1)))
+		r.NoError(err)
+		calls = append(calls, call)
+	}
+	calls[1].Failed = true
+	calls[2].RawReturn = calls[2].RawReturn[:32]
+
+	states, errs := DecodeAll[decodeState](calls)
+	r.Len(states, 3)
+	r.Len(errs, 3)
+	r.NoError(errs[0])
+	r.Equal(owner, states[0].Owner)
+	r.Equal(big.NewInt(1), states[0].Amount)
+	r.ErrorIs(errs[1], ErrCallFailed)
+	r.Zero(states[1])
+	r.Error(errs[2])
+}
@@ -0,0 +1,70 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CallDeduped makes multicalls like Call but sends the calls with the same target and
+// calldata only once. The result is then unpacked into each of the calls. A deduplicated
+// call is allowed to fail only if all of its duplicates are allowed to fail.
+func (caller *Caller) CallDeduped(opts *bind.CallOpts, calls ...*Call) ([]*Call, error) {
+	var (
+		multiErr MultiError
+		unique   []*Call
+		keys     = make(map[common.Hash]int)
+		indexes  = make([]int, len(calls)) // call index -> unique call index
+	)
+	for i, call := range calls {
+		b, err := call.Pack()
+		if err != nil {
+			if caller.strict {
+				return calls, fmt.Errorf("failed to pack call inputs at index [%d] %s: %v", i, describeCall(call), err)
+			}
+			multiErr.add(i, call, fmt.Errorf("failed to pack call inputs: %v", err))
+			indexes[i] = -1
+			continue
+		}
+
+		key := crypto.Keccak256Hash(call.Contract.Address.Bytes(), b)
+		j, ok := keys[key]
+		if !ok {
+			j = len(unique)
+			keys[key] = j
+			unique = append(unique, NewRawCall(call.Contract.Address, b, true))
+		}
+		unique[j].CanFail = unique[j].CanFail && call.CanFail
+		indexes[i] = j
+	}
+
+	if len(unique) > 0 || len(calls) == 0 {
+		// raw calls do not unpack, so only the multicall itself can fail here
+		_, err := caller.Call(opts, unique...)
+		if uniqueErr := (*MultiError)(nil); err != nil && !errors.As(err, &uniqueErr) {
+			return calls, err
+		}
+	}
+
+	for i, call := range calls {
+		if indexes[i] < 0 {
+			continue
+		}
+		result := unique[indexes[i]]
+		call.Failed = result.Failed
+		call.setRawReturn(result.RawReturn)
+		call.UnpackErr = nil
+		if call.Failed {
+			continue
+		}
+		if err := caller.unpackCall(i, call, result.RawReturn); err != nil {
+			return calls, err
+		}
+	}
+
+	collectCallErrors(&multiErr, calls)
+	return calls, multiErr.errOrNil()
+}
@@ -0,0 +1,46 @@
+package multicall
+
+import (
+	"testing"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_CallDeduped(t *testing.T) {
+	r := require.New(t)
+
+	testContract1, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	testContract2, err := NewContract(oneValueABI, testAddr2)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract1.NewCall(new(boolOutput), "testFunc", true),
+		testContract1.NewCall(new(boolOutput), "testFunc", false),
+		testContract1.NewCall(new(boolOutput), "testFunc", true).AllowFailure(),
+		testContract2.NewCall(new(boolOutput), "testFunc", true),
+		testContract1.NewCall(new(boolOutput), "testFunc", false),
+	}
+
+	var sent []contract_multicall.Multicall3Call3
+	stub := echoStub()
+	returnData := stub.returnData
+	stub.returnData = func(calls []contract_multicall.Multicall3Call3) [][]byte {
+		sent = calls
+		return returnData(calls)
+	}
+	caller := &Caller{contract: stub}
+
+	results, err := caller.CallDeduped(nil, calls...)
+	r.NoError(err)
+	r.Len(sent, 3)
+	r.False(sent[0].AllowFailure)
+
+	r.Len(results, 5)
+	for i, expected := range []bool{true, false, true, true, false} {
+		r.Equal(expected, results[i].Outputs.(*boolOutput).Val1)
+		r.NotEmpty(results[i].RawReturn)
+	}
+	r.NotSame(results[0].Outputs, results[2].Outputs)
+}
@@ -0,0 +1,91 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+)
+
+// withDefaultContext wraps the contract so that the calls without a context use the
+// default context of the caller, if there is any.
+func (caller *Caller) withDefaultContext(contract contract_multicall.Interface) contract_multicall.Interface {
+	if caller.defaultCtx == nil {
+		return contract
+	}
+	if dc, ok := contract.(*defaultContextContract); ok {
+		contract = dc.contract
+	}
+	return &defaultContextContract{contract: contract, ctx: caller.defaultCtx}
+}
+
+// defaultContextContract sets the default context on the call options which have none.
+type defaultContextContract struct {
+	contract contract_multicall.Interface
+	ctx      context.Context
+}
+
+func (dc *defaultContextContract) Aggregate(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call) (struct {
+	BlockNumber *big.Int
+	ReturnData  [][]byte
+}, error) {
+	return dc.contract.Aggregate(withContext(dc.ctx, opts), calls)
+}
+
+func (dc *defaultContextContract) Aggregate3(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call3) ([]contract_multicall.Multicall3Result, error) {
+	return dc.contract.Aggregate3(withContext(dc.ctx, opts), calls)
+}
+
+func (dc *defaultContextContract) BlockAndAggregate(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call) (struct {
+	BlockNumber *big.Int
+	BlockHash   [32]byte
+	ReturnData  []contract_multicall.Multicall3Result
+}, error) {
+	return dc.contract.BlockAndAggregate(withContext(dc.ctx, opts), calls)
+}
+
+func (dc *defaultContextContract) GetBasefee(opts *bind.CallOpts) (*big.Int, error) {
+	return dc.contract.GetBasefee(withContext(dc.ctx, opts))
+}
+
+func (dc *defaultContextContract) GetBlockHash(opts *bind.CallOpts, blockNumber *big.Int) ([32]byte, error) {
+	return dc.contract.GetBlockHash(withContext(dc.ctx, opts), blockNumber)
+}
+
+func (dc *defaultContextContract) GetBlockNumber(opts *bind.CallOpts) (*big.Int, error) {
+	return dc.contract.GetBlockNumber(withContext(dc.ctx, opts))
+}
+
+func (dc *defaultContextContract) GetChainId(opts *bind.CallOpts) (*big.Int, error) {
+	return dc.contract.GetChainId(withContext(dc.ctx, opts))
+}
+
+func (dc *defaultContextContract) GetCurrentBlockCoinbase(opts *bind.CallOpts) (common.Address, error) {
+	return dc.contract.GetCurrentBlockCoinbase(withContext(dc.ctx, opts))
+}
+
+func (dc *defaultContextContract) GetCurrentBlockDifficulty(opts *bind.CallOpts) (*big.Int, error) {
+	return dc.contract.GetCurrentBlockDifficulty(withContext(dc.ctx, opts))
+}
+
+func (dc *defaultContextContract) GetCurrentBlockGasLimit(opts *bind.CallOpts) (*big.Int, error) {
+	return dc.contract.GetCurrentBlockGasLimit(withContext(dc.ctx, opts))
+}
+
+func (dc *defaultContextContract) GetCurrentBlockTimestamp(opts *bind.CallOpts) (*big.Int, error) {
+	return dc.contract.GetCurrentBlockTimestamp(withContext(dc.ctx, opts))
+}
+
+func (dc *defaultContextContract) TryAggregate(opts *bind.CallOpts, requireSuccess bool, calls []contract_multicall.Multicall3Call) ([]contract_multicall.Multicall3Result, error) {
+	return dc.contract.TryAggregate(withContext(dc.ctx, opts), requireSuccess, calls)
+}
+
+func (dc *defaultContextContract) TryBlockAndAggregate(opts *bind.CallOpts, requireSuccess bool, calls []contract_multicall.Multicall3Call) (struct {
+	BlockNumber *big.Int
+	BlockHash   [32]byte
+	ReturnData  []contract_multicall.Multicall3Result
+}, error) {
+	return dc.contract.TryBlockAndAggregate(withContext(dc.ctx, opts), requireSuccess, calls)
+}
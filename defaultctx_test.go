@@ -0,0 +1,51 @@
+package multicall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func TestCaller_DefaultContext(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	call := testContract.NewCall(new(boolOutput), "testFunc", true)
+
+	defaultCtx := context.WithValue(context.Background(), ctxKey{}, "default")
+	var values []any
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		values = append(values, opts.Context.Value(ctxKey{}))
+	}
+	caller := NewWithContract(stub, WithDefaultContext(defaultCtx))
+
+	_, err = caller.Call(nil, call)
+	r.NoError(err)
+	_, err = caller.CallChunked(&bind.CallOpts{}, 1, 0, call)
+	r.NoError(err)
+	_, err = caller.CallConcurrent(nil, 1, 1, call)
+	r.NoError(err)
+	_, err = caller.CallPending(call)
+	r.NoError(err)
+	otherCtx := context.WithValue(context.Background(), ctxKey{}, "other")
+	_, err = caller.Call(&bind.CallOpts{Context: otherCtx}, call)
+	r.NoError(err)
+	r.Equal([]any{"default", "default", "default", "default", "other"}, values)
+
+	// the calls with the backend client use the default context too
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	caller, err = New(&slowClient{}, WithDefaultContext(cancelledCtx))
+	r.NoError(err)
+	_, err = caller.Call(nil, call)
+	r.ErrorIs(err, context.Canceled)
+	_, err = caller.CallAs(nil, common.HexToAddress(testAddr2), call)
+	r.ErrorContains(err, context.Canceled.Error())
+}
@@ -0,0 +1,91 @@
+package multicall
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type erc20BalanceOutput struct {
+	Balance *big.Int
+}
+
+// ERC20Balances gets the balances of the holders for each token by using the balanceOf
+// method of the tokens. The balances are returned by the token and then by the holder.
+// Duplicate tokens and holders are queried once and the calls are chunked.
+func (caller *Caller) ERC20Balances(opts *bind.CallOpts, tokens []common.Address, holders []common.Address) (map[common.Address]map[common.Address]*big.Int, error) {
+	var calls []*Call
+	seenTokens := make(map[common.Address]bool)
+	for _, token := range tokens {
+		if seenTokens[token] {
+			continue
+		}
+		seenTokens[token] = true
+		contract := NewERC20(token)
+		seenHolders := make(map[common.Address]bool)
+		for _, holder := range holders {
+			if seenHolders[holder] {
+				continue
+			}
+			seenHolders[holder] = true
+			calls = append(calls, contract.NewCall(new(erc20BalanceOutput), "balanceOf", holder))
+		}
+	}
+
+	calls, err := caller.CallChunked(opts, caller.helperChunkSize(), caller.cooldown, calls...)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[common.Address]map[common.Address]*big.Int)
+	for _, call := range calls {
+		token := call.Contract.Address
+		if balances[token] == nil {
+			balances[token] = make(map[common.Address]*big.Int)
+		}
+		balances[token][call.Inputs[0].(common.Address)] = call.Outputs.(*erc20BalanceOutput).Balance
+	}
+	return balances, nil
+}
+
+// Portfolio is the native balance and the token balances of a holder at the same block.
+type Portfolio struct {
+	Native *big.Int
+	Tokens map[common.Address]*big.Int
+}
+
+// Portfolio gets the native balance of the holder by using the getEthBalance method of the
+// multicall contract and the balances of the tokens by using their balanceOf method. All
+// balances are read with a single multicall, so they are consistent with each other.
+// Duplicate tokens are queried once.
+func (caller *Caller) Portfolio(opts *bind.CallOpts, holder common.Address, tokens ...common.Address) (*Portfolio, error) {
+	multicall, err := caller.multicallContract()
+	if err != nil {
+		return nil, err
+	}
+	calls := []*Call{multicall.NewCall(new(ethBalanceOutput), "getEthBalance", holder)}
+	seen := make(map[common.Address]bool)
+	for _, token := range tokens {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		contract := NewERC20(token)
+		calls = append(calls, contract.NewCall(new(erc20BalanceOutput), "balanceOf", holder))
+	}
+
+	calls, err = caller.Call(opts, calls...)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := &Portfolio{
+		Native: calls[0].Outputs.(*ethBalanceOutput).Balance,
+		Tokens: make(map[common.Address]*big.Int),
+	}
+	for _, call := range calls[1:] {
+		portfolio.Tokens[call.Contract.Address] = call.Outputs.(*erc20BalanceOutput).Balance
+	}
+	return portfolio, nil
+}
@@ -0,0 +1,69 @@
+package multicall
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_ERC20Balances(t *testing.T) {
+	r := require.New(t)
+
+	token1 := common.HexToAddress(testAddr1)
+	token2 := common.HexToAddress(testAddr2)
+	holder1 := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	holder2 := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	var callCount int
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		callCount += len(calls)
+		return nil
+	}
+	caller := &Caller{contract: stub, chunkSize: 3}
+
+	// the stub returns the holder address as the balance
+	balances, err := caller.ERC20Balances(nil, []common.Address{token1, token2, token1}, []common.Address{holder1, holder2, holder2})
+	r.NoError(err)
+	r.Equal(4, callCount)
+	r.Equal(map[common.Address]map[common.Address]*big.Int{
+		token1: {holder1: big.NewInt(1), holder2: big.NewInt(2)},
+		token2: {holder1: big.NewInt(1), holder2: big.NewInt(2)},
+	}, balances)
+}
+
+func TestCaller_Portfolio(t *testing.T) {
+	r := require.New(t)
+
+	token1 := common.HexToAddress(testAddr1)
+	token2 := common.HexToAddress(testAddr2)
+	holder := common.HexToAddress("0x0000000000000000000000000000000000000005")
+
+	var targets []common.Address
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		for _, call := range calls {
+			targets = append(targets, call.Target)
+		}
+		return nil
+	}
+	caller := &Caller{contract: stub, address: common.HexToAddress(DefaultAddress)}
+
+	// the stub returns the holder address as the balance
+	portfolio, err := caller.Portfolio(nil, holder, token1, token2, token1)
+	r.NoError(err)
+	r.Equal([]common.Address{common.HexToAddress(DefaultAddress), token1, token2}, targets)
+	r.Equal(big.NewInt(5), portfolio.Native)
+	r.Equal(map[common.Address]*big.Int{
+		token1: big.NewInt(5),
+		token2: big.NewInt(5),
+	}, portfolio.Tokens)
+
+	portfolio, err = caller.Portfolio(nil, holder)
+	r.NoError(err)
+	r.Equal(big.NewInt(5), portfolio.Native)
+	r.Empty(portfolio.Tokens)
+}
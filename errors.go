@@ -0,0 +1,204 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrCallFailed is the error for the calls which failed on chain.
+var ErrCallFailed = errors.New("call failed on chain")
+
+// ErrBaseFeeUnsupported is the error for reading the base fee on a chain which does not
+// support EIP-1559.
+var ErrBaseFeeUnsupported = errors.New("base fee is not supported on chain")
+
+// ErrTooManyCalls is the error for the multicalls which exceed the calls per aggregate
+// limit, see WithMaxCallsPerAggregate.
+var ErrTooManyCalls = errors.New("too many calls")
+
+// ErrInvalidChunkSize is the error of the chunked methods for a chunk size which is not
+// positive.
+var ErrInvalidChunkSize = errors.New("invalid chunk size")
+
+// ErrBatchReverted is matched by the errors of the multicalls which reverted as a whole,
+// rather than failing to be sent. Use errors.As with *BatchRevertError to get the revert
+// data.
+var ErrBatchReverted = errors.New("multicall reverted")
+
+// BatchRevertError is the revert of the multicall contract call itself, e.g. when the batch
+// runs out of gas or a call which does not allow failure reverts.
+type BatchRevertError struct {
+	Data []byte
+	Err  error
+}
+
+// Error implements the error interface.
+func (err *BatchRevertError) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err *BatchRevertError) Unwrap() error {
+	return err.Err
+}
+
+// Is reports whether the target is ErrBatchReverted.
+func (err *BatchRevertError) Is(target error) bool {
+	return target == ErrBatchReverted
+}
+
+// multicallError wraps the error of a multicall, detecting the reverts of the batch. The
+// error is wrapped so that context errors can be matched with errors.Is.
+func multicallError(err error) error {
+	if isRevert(err) {
+		return fmt.Errorf("multicall failed: %w", &BatchRevertError{Data: revertData(err), Err: err})
+	}
+	return fmt.Errorf("multicall failed: %w", &sendError{err: err})
+}
+
+// sendError is the error of sending a multicall which did not revert, e.g. a connection or
+// a rate limit error, so the multicall may succeed when it is retried.
+type sendError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (err *sendError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err *sendError) Unwrap() error {
+	return err.err
+}
+
+// CallError is the failure of a single call in a batch. Selector is the method selector of
+// the call, which is nil when it is unknown.
+type CallError struct {
+	Index    int
+	Target   common.Address
+	Selector []byte
+	Err      error
+}
+
+// Error implements the error interface.
+func (err *CallError) Error() string {
+	return fmt.Sprintf("call at index [%d] %s: %v", err.Index, describeTarget(err.Target, err.Selector), err.Err)
+}
+
+// describeCall describes the target and the method selector of the call for the errors.
+func describeCall(call *Call) string {
+	return describeTarget(callTarget(call), call.selector())
+}
+
+func describeTarget(target common.Address, selector []byte) string {
+	if len(selector) == 0 {
+		return "to " + target.Hex()
+	}
+	return fmt.Sprintf("to %s (selector %s)", target.Hex(), hexutil.Encode(selector))
+}
+
+// Unwrap returns the underlying error.
+func (err *CallError) Unwrap() error {
+	return err.Err
+}
+
+// ChunkError is the failure of a whole chunk of the chunked methods. Indexes are the
+// indexes of the calls of the chunk in the given calls.
+type ChunkError struct {
+	Chunk   int
+	Indexes []int
+	Err     error
+}
+
+// Error implements the error interface.
+func (err *ChunkError) Error() string {
+	return fmt.Sprintf("call chunk [%d] failed: %v", err.Chunk, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *ChunkError) Unwrap() error {
+	return err.Err
+}
+
+// newChunkError returns the error of the chunk which has the calls from the offset.
+func newChunkError(chunk, offset, size int, err error) *ChunkError {
+	indexes := make([]int, size)
+	for i := range indexes {
+		indexes[i] = offset + i
+	}
+	return &ChunkError{Chunk: chunk, Indexes: indexes, Err: err}
+}
+
+// MultiError collects the failures of the calls in a batch. The calls which are not
+// listed have succeeded.
+type MultiError struct {
+	Errors []*CallError
+}
+
+// Error implements the error interface.
+func (err *MultiError) Error() string {
+	msgs := make([]string, 0, len(err.Errors))
+	for _, callErr := range err.Errors {
+		msgs = append(msgs, callErr.Error())
+	}
+	return fmt.Sprintf("%d call(s) failed: %s", len(err.Errors), strings.Join(msgs, "; "))
+}
+
+// Is reports whether any of the call errors matches the target.
+func (err *MultiError) Is(target error) bool {
+	for _, callErr := range err.Errors {
+		if errors.Is(callErr, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (err *MultiError) add(index int, call *Call, callErr error) {
+	entry := &CallError{Index: index, Err: callErr}
+	if call != nil {
+		entry.Target = callTarget(call)
+		entry.Selector = call.selector()
+	}
+	err.Errors = append(err.Errors, entry)
+}
+
+// merge adds the errors of a chunk by shifting the indexes by the chunk offset.
+func (err *MultiError) merge(offset int, chunkErr *MultiError) {
+	for _, callErr := range chunkErr.Errors {
+		shifted := *callErr
+		shifted.Index += offset
+		err.Errors = append(err.Errors, &shifted)
+	}
+}
+
+func (err *MultiError) sort() {
+	sort.Slice(err.Errors, func(i, j int) bool {
+		return err.Errors[i].Index < err.Errors[j].Index
+	})
+}
+
+func (err *MultiError) errOrNil() error {
+	if len(err.Errors) == 0 {
+		return nil
+	}
+	return err
+}
+
+// isRevert tells if the error is from a call which reverted or hit an invalid opcode,
+// rather than an error of the connection.
+func isRevert(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "execution reverted") || strings.Contains(msg, "invalid opcode")
+}
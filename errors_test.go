@@ -0,0 +1,201 @@
+package multicall
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_MultiError(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+		testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure(),
+		testContract.NewCall(new([]struct{}), "testFunc", true), // bad outputs type
+	}
+
+	stub := echoStub()
+	stub.failures = map[int]bool{1: true} // index in the packed batch
+	caller := &Caller{contract: stub}
+
+	results, err := caller.Call(nil, calls...)
+	r.Error(err)
+	r.Len(results, 4)
+	r.True(results[0].Outputs.(*boolOutput).Val1)
+
+	var multiErr *MultiError
+	r.True(errors.As(err, &multiErr))
+	r.Len(multiErr.Errors, 3)
+	r.Equal(1, multiErr.Errors[0].Index)
+	r.ErrorContains(multiErr.Errors[0], "pack")
+	r.Equal(2, multiErr.Errors[1].Index)
+	r.ErrorIs(multiErr.Errors[1], ErrCallFailed)
+	r.ErrorIs(err, ErrCallFailed)
+	r.Equal(3, multiErr.Errors[2].Index)
+	r.ErrorContains(multiErr.Errors[2], "not a struct")
+	r.Equal(common.HexToAddress(testAddr1), multiErr.Errors[2].Target)
+}
+
+func TestCaller_MultiErrorChunked(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 6; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure())
+	}
+
+	stub := echoStub()
+	stub.failures = map[int]bool{1: true}
+	caller := &Caller{contract: stub}
+
+	for _, call := range []func() ([]*Call, error){
+		func() ([]*Call, error) { return caller.CallChunked(nil, 2, 0, calls...) },
+		func() ([]*Call, error) { return caller.CallConcurrent(nil, 2, 3, calls...) },
+	} {
+		results, err := call()
+		r.Len(results, 6)
+
+		var multiErr *MultiError
+		r.True(errors.As(err, &multiErr))
+		r.Len(multiErr.Errors, 3)
+		for i, callErr := range multiErr.Errors {
+			r.Equal(i*2+1, callErr.Index)
+		}
+	}
+}
+
+func TestCaller_MultiErrorTransport(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("connection refused")
+	}
+	caller := &Caller{contract: stub}
+
+	_, err = caller.Call(nil, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.Error(err)
+	var multiErr *MultiError
+	r.False(errors.As(err, &multiErr))
+}
+
+type dataErrorStub struct {
+	data string
+}
+
+func (err *dataErrorStub) Error() string {
+	return "execution reverted"
+}
+
+func (err *dataErrorStub) ErrorData() interface{} {
+	return err.data
+}
+
+func TestCaller_BatchReverted(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	call := testContract.NewCall(new(boolOutput), "testFunc", true)
+
+	caller := &Caller{contract: &multicallStub{callErr: func([]contract_multicall.Multicall3Call3) error {
+		return &dataErrorStub{data: "0x01020304"}
+	}}}
+	_, err = caller.Call(nil, call)
+	r.ErrorIs(err, ErrBatchReverted)
+	r.EqualError(err, "multicall failed: execution reverted")
+	var revertErr *BatchRevertError
+	r.True(errors.As(err, &revertErr))
+	r.Equal([]byte{0x01, 0x02, 0x03, 0x04}, revertErr.Data)
+
+	caller = &Caller{contract: &multicallStub{callErr: func([]contract_multicall.Multicall3Call3) error {
+		return errors.New("connection refused")
+	}}}
+	_, err = caller.Call(nil, call)
+	r.Error(err)
+	r.NotErrorIs(err, ErrBatchReverted)
+}
+
+func TestCaller_ChunkError(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 7; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	// the second chunk fails
+	var chunks int
+	stub := echoStub()
+	stub.callErr = func([]contract_multicall.Multicall3Call3) error {
+		chunks++
+		if chunks == 2 {
+			return errors.New("rpc down")
+		}
+		return nil
+	}
+	caller := &Caller{contract: stub}
+
+	results, err := caller.CallChunked(nil, 3, 0, calls...)
+	r.EqualError(err, "call chunk [1] failed: multicall failed: rpc down")
+	// only the calls of the first chunk are returned
+	r.Equal(calls[:3], results)
+	for _, call := range results {
+		r.True(call.Outputs.(*boolOutput).Val1)
+	}
+	var chunkErr *ChunkError
+	r.ErrorAs(err, &chunkErr)
+	r.Equal(1, chunkErr.Chunk)
+	r.Equal([]int{3, 4, 5}, chunkErr.Indexes)
+
+	// the calls which fail to pack are not in the chunks
+	chunks = 0
+	calls[1].Inputs = []any{'a'} // bad input
+	results, err = caller.CallByteLimited(nil, aggregate3Overhead+3*(call3Overhead+64), calls...)
+	r.ErrorAs(err, &chunkErr)
+	r.Equal(1, chunkErr.Chunk)
+	r.Equal([]int{4, 5, 6}, chunkErr.Indexes)
+	// the call which failed to pack goes with the completed chunk
+	r.Equal(calls[:4], results)
+}
+
+func TestCaller_ErrorDescribesCall(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+	}
+	caller := &Caller{contract: echoStub()}
+	_, err = caller.Call(nil, calls...)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Equal([]byte{0x76, 0xa6, 0xe4, 0xc2}, multiErr.Errors[0].Selector)
+	r.ErrorContains(multiErr.Errors[0], "call at index [1] to "+testAddr1+" (selector 0x76a6e4c2)")
+
+	_, err = caller.Strict().Call(nil, calls...)
+	r.ErrorContains(err, "failed to pack call inputs at index [1] to "+testAddr1+" (selector 0x76a6e4c2)")
+
+	raw := NewRawCall(common.HexToAddress(testAddr2), []byte{1, 2}, false)
+	r.Equal("to "+common.HexToAddress(testAddr2).Hex(), describeCall(raw))
+}
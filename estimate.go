@@ -0,0 +1,52 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// gasEstimator is a backend client which can estimate gas, like a bind.ContractTransactor.
+type gasEstimator interface {
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+}
+
+// EstimateGas estimates the gas of the aggregate3Value transaction which would be sent by
+// CallValue with given calls. The backend client must be able to estimate gas.
+func (caller *Caller) EstimateGas(opts *bind.CallOpts, calls ...*Call) (uint64, error) {
+	estimator, ok := caller.client.(gasEstimator)
+	if !ok {
+		return 0, errors.New("caller backend cannot estimate gas")
+	}
+
+	multiCalls, total, err := packCalls3Value(calls)
+	if err != nil {
+		return 0, err
+	}
+	multicall, err := caller.multicallContract()
+	if err != nil {
+		return 0, err
+	}
+	data, err := multicall.ABI.Pack("aggregate3Value", multiCalls)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pack multicall: %v", err)
+	}
+
+	ctx, _ := caller.callContext(opts)
+	msg := ethereum.CallMsg{
+		To:    &caller.address,
+		Value: total,
+		Data:  data,
+	}
+	if opts != nil {
+		msg.From = opts.From
+	}
+	gas, err := estimator.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	return gas, nil
+}
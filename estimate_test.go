@@ -0,0 +1,51 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type estimatorStub struct {
+	clientStub
+	msg ethereum.CallMsg
+}
+
+func (es *estimatorStub) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	es.msg = call
+	return 21000 + uint64(len(call.Data)), nil
+}
+
+func TestCaller_EstimateGas(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	client := &estimatorStub{}
+	caller, err := New(client)
+	r.NoError(err)
+
+	from := common.HexToAddress(testAddr2)
+	gas, err := caller.EstimateGas(&bind.CallOpts{From: from},
+		testContract.NewCall(nil, "testFunc", true).WithValue(big.NewInt(100)),
+		testContract.NewCall(nil, "testFunc", false),
+	)
+	r.NoError(err)
+	r.Equal(21000+uint64(len(client.msg.Data)), gas)
+	r.Equal(from, client.msg.From)
+	r.Equal(common.HexToAddress(DefaultAddress), *client.msg.To)
+	r.Equal(big.NewInt(100), client.msg.Value)
+
+	multicall, err := caller.multicallContract()
+	r.NoError(err)
+	r.Equal(multicall.ABI.Methods["aggregate3Value"].ID, client.msg.Data[:4])
+
+	_, err = (&Caller{client: &clientStub{}}).EstimateGas(nil)
+	r.EqualError(err, "caller backend cannot estimate gas")
+}
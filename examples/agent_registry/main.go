@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -92,11 +93,16 @@ func main() {
 			new(agentState),
 			"getAgentState",
 			botHexToBigInt("0x80ed808b586aeebe9cdd4088ea4dea0a8e322909c0e4493c993e060e89c09ed1"),
-		),
+		).AllowFailure(),
 	)
-	if err != nil {
+	// a call which is allowed to fail is marked failed and reported in a *multicall.MultiError
+	if err != nil && !errors.As(err, new(*multicall.MultiError)) {
 		panic(err)
 	}
+	if calls[0].Failed || calls[0].UnpackErr != nil {
+		fmt.Println("failed to get the agent state")
+		return
+	}
 	fmt.Println("owner:", calls[0].Outputs.(*agentState).Owner.String())
 
 	b, _ := json.MarshalIndent(calls[0].Outputs.(*agentState), "", "	")
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -59,12 +60,18 @@ func main() {
 			new(balanceOutput),
 			"balanceOf",
 			common.HexToAddress("0x40ec5B33f54e0E8A33A975908C5BA1c14e5BbbDf"), // Polygon ERC20 bridge
-		).Name("Polygon ERC20 bridge balance"),
+		).Name("Polygon ERC20 bridge balance").AllowFailure(),
 	)
-	if err != nil {
+	// the calls which are allowed to fail are reported in a *multicall.MultiError and
+	// marked failed, while the other calls have their results
+	if err != nil && !errors.As(err, new(*multicall.MultiError)) {
 		panic(err)
 	}
 	for _, call := range calls {
+		if call.Failed || call.UnpackErr != nil {
+			fmt.Println(call.CallName, ": failed")
+			continue
+		}
 		fmt.Println(call.CallName, ":", call.Outputs.(*balanceOutput).Balance)
 	}
 }
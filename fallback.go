@@ -0,0 +1,154 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CallWithFallback makes multicalls like Call but falls back to making each call separately
+// when the multicall contract is not deployed. As in the multicall contract, a failing call
+// fails the whole batch unless it is allowed to fail.
+func (caller *Caller) CallWithFallback(opts *bind.CallOpts, calls ...*Call) ([]*Call, error) {
+	deployed, err := caller.isDeployed(opts)
+	if err != nil {
+		return calls, err
+	}
+	if deployed {
+		return caller.Call(opts, calls...)
+	}
+	return caller.callEach(opts, calls...)
+}
+
+// CallAs makes each call separately with the backend client from given address, so the
+// address is the msg.sender of the calls. This is needed for the view functions which
+// depend on msg.sender since the msg.sender of the calls in a multicall is the multicall
+// contract, even when the call options have a From address.
+func (caller *Caller) CallAs(opts *bind.CallOpts, from common.Address, calls ...*Call) ([]*Call, error) {
+	var fromOpts bind.CallOpts
+	if opts != nil {
+		fromOpts = *opts
+	}
+	fromOpts.From = from
+	return caller.callEach(&fromOpts, calls...)
+}
+
+func (caller *Caller) isDeployed(opts *bind.CallOpts) (bool, error) {
+	if caller.client == nil {
+		return false, errors.New("caller has no backend client")
+	}
+	ctx, blockNumber := caller.callContext(opts)
+	code, err := caller.client.CodeAt(ctx, caller.address, blockNumber)
+	if err != nil {
+		return false, fmt.Errorf("failed to get multicall contract code: %v", err)
+	}
+	return len(code) > 0, nil
+}
+
+// callEach makes the calls one by one with the backend client.
+func (caller *Caller) callEach(opts *bind.CallOpts, calls ...*Call) ([]*Call, error) {
+	if caller.client == nil {
+		return calls, errors.New("caller has no backend client")
+	}
+	var multiErr MultiError
+	for i, call := range calls {
+		b, err := call.Pack()
+		if err != nil {
+			if caller.strict {
+				return calls, fmt.Errorf("failed to pack call inputs at index [%d] %s: %v", i, describeCall(call), err)
+			}
+			multiErr.add(i, call, fmt.Errorf("failed to pack call inputs: %v", err))
+			continue
+		}
+
+		if err := caller.callContract(opts, call, b); err != nil {
+			return calls, fmt.Errorf("call at index [%d] %s failed: %w", i, describeCall(call), err)
+		}
+		if call.Failed {
+			continue
+		}
+		if err := caller.unpackCall(i, call, call.RawReturn); err != nil {
+			return calls, err
+		}
+	}
+
+	collectCallErrors(&multiErr, calls)
+	return calls, multiErr.errOrNil()
+}
+
+// callContext returns the context and the block number of the call options. The default
+// context of the caller is used when the options have no context.
+func (caller *Caller) callContext(opts *bind.CallOpts) (ctx context.Context, blockNumber *big.Int) {
+	ctx = context.Background()
+	if caller.defaultCtx != nil {
+		ctx = caller.defaultCtx
+	}
+	if opts == nil {
+		return
+	}
+	if opts.Context != nil {
+		ctx = opts.Context
+	}
+	return ctx, opts.BlockNumber
+}
+
+// callContract makes the call separately with the backend client using its gas limit and
+// sets the results. Only the reverts, including running out of the gas limit of the call,
+// are failures of the call, and they are returned for a call which is not allowed to fail.
+// The other errors, like the connection errors, are always returned.
+func (caller *Caller) callContract(opts *bind.CallOpts, call *Call, data []byte) error {
+	ctx, blockNumber := caller.callContext(opts)
+	msg := ethereum.CallMsg{
+		To:   &call.Contract.Address,
+		Gas:  call.Gas,
+		Data: data,
+	}
+	if opts != nil {
+		msg.From = opts.From
+	}
+	returnData, err := caller.client.CallContract(ctx, msg, blockNumber)
+	if err != nil && !isCallFailure(err) {
+		return err
+	}
+	call.Failed = err != nil
+	call.setRawReturn(returnData)
+	call.UnpackErr = nil
+	if err != nil {
+		if !call.CanFail {
+			return err
+		}
+		call.setRawReturn(revertData(err))
+	}
+	return nil
+}
+
+// isCallFailure tells if the error of a single call is the failure of the call itself,
+// which is a revert or running out of its gas limit.
+func isCallFailure(err error) bool {
+	return isRevert(err) || strings.Contains(err.Error(), "out of gas")
+}
+
+// revertData extracts the revert data from a JSON-RPC error if there is any.
+func revertData(err error) []byte {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,133 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// clientStub is a backend which has the given code at every address and returns the
+// inputs of each call as its outputs.
+type clientStub struct {
+	code      []byte
+	callCount int
+	callErr   error
+	gas       []uint64
+	from      []common.Address
+}
+
+func (cs *clientStub) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return cs.code, nil
+}
+
+func (cs *clientStub) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	cs.callCount++
+	cs.gas = append(cs.gas, call.Gas)
+	cs.from = append(cs.from, call.From)
+	if cs.callErr != nil {
+		return nil, cs.callErr
+	}
+	return call.Data[4:], nil
+}
+
+func (cs *clientStub) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(testChainID), nil
+}
+
+func TestCaller_Verify(t *testing.T) {
+	r := require.New(t)
+
+	caller, err := New(&clientStub{})
+	r.NoError(err)
+	err = caller.verify(context.Background())
+	r.EqualError(err, "no multicall contract deployed at 0xcA11bde05977b3631167028862bE2a173976CA11 on chain 137")
+
+	caller, err = New(&clientStub{code: []byte{0x60, 0x80}})
+	r.NoError(err)
+	r.NoError(caller.verify(context.Background()))
+}
+
+func TestCaller_CallWithFallback(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(new(boolOutput), "testFunc", true)
+	call2 := testContract.NewCall(new(boolOutput), "testFunc", false)
+
+	client := &clientStub{}
+	caller := &Caller{client: client, contract: &multicallStub{}}
+
+	calls, err := caller.CallWithFallback(nil, call1, call2)
+	r.NoError(err)
+	r.Equal(2, client.callCount)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.False(calls[1].Outputs.(*boolOutput).Val1)
+	r.False(calls[0].Failed)
+}
+
+func TestCaller_CallWithFallbackFailure(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	client := &clientStub{callErr: errors.New("execution reverted")}
+	caller := &Caller{client: client, contract: &multicallStub{}}
+
+	calls, err := caller.CallWithFallback(nil, testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure())
+	r.ErrorIs(err, ErrCallFailed)
+	r.True(calls[0].Failed)
+
+	_, err = caller.CallWithFallback(nil, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorContains(err, "execution reverted")
+
+	// the errors other than reverts are not call failures
+	client.callErr = errors.New("connection refused")
+	calls, err = caller.CallWithFallback(nil, testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure())
+	r.ErrorContains(err, "connection refused")
+	r.NotErrorIs(err, ErrCallFailed)
+	r.False(calls[0].Failed)
+}
+
+func TestCaller_CallWithFallbackDeployed(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	client := &clientStub{code: []byte{0x60, 0x80}}
+	caller := &Caller{client: client, contract: echoStub()}
+
+	calls, err := caller.CallWithFallback(nil, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.NoError(err)
+	r.Equal(0, client.callCount)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+}
+
+func TestCaller_CallAs(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	from := common.HexToAddress(testAddr2)
+	client := &clientStub{}
+	caller := &Caller{client: client, contract: &multicallStub{}}
+
+	calls, err := caller.CallAs(&bind.CallOpts{From: common.HexToAddress(testAddr1)}, from,
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", false),
+	)
+	r.NoError(err)
+	r.Equal([]common.Address{from, from}, client.from)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.False(calls[1].Outputs.(*boolOutput).Val1)
+}
@@ -0,0 +1,57 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+)
+
+// splitGasCalls separates the calls which have a gas limit from the rest.
+func splitGasCalls(calls []*Call, multiCalls []contract_multicall.Multicall3Call3) (
+	aggCalls []*Call, aggMultiCalls []contract_multicall.Multicall3Call3,
+	gasCalls []*Call, gasMultiCalls []contract_multicall.Multicall3Call3,
+) {
+	for i, call := range calls {
+		if call.Gas > 0 {
+			gasCalls = append(gasCalls, call)
+			gasMultiCalls = append(gasMultiCalls, multiCalls[i])
+			continue
+		}
+		aggCalls = append(aggCalls, call)
+		aggMultiCalls = append(aggMultiCalls, multiCalls[i])
+	}
+	return
+}
+
+// callWithGas makes the calls which have a gas limit separately with eth_call.
+func (caller *Caller) callWithGas(opts *bind.CallOpts, calls []*Call, gasCalls []*Call, gasMultiCalls []contract_multicall.Multicall3Call3) error {
+	if len(gasCalls) == 0 {
+		return nil
+	}
+	if caller.client == nil {
+		return errors.New("caller has no backend client for the calls with gas limit")
+	}
+	for i, call := range gasCalls {
+		if err := caller.callContract(opts, call, gasMultiCalls[i].CallData); err != nil {
+			return fmt.Errorf("call at index [%d] %s failed: %w", indexOf(calls, call), describeCall(call), err)
+		}
+		if call.Failed {
+			continue
+		}
+		if err := caller.unpackCall(indexOf(calls, call), call, call.RawReturn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexOf(calls []*Call, call *Call) int {
+	for i := range calls {
+		if calls[i] == call {
+			return i
+		}
+	}
+	return -1
+}
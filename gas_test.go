@@ -0,0 +1,61 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_CallWithGas(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(new(boolOutput), "testFunc", true)
+	call2 := testContract.NewCall(new(boolOutput), "testFunc", true).WithGas(50_000_000)
+	call3 := testContract.NewCall(new(boolOutput), "testFunc", false)
+
+	var sent int
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		sent += len(calls)
+		return nil
+	}
+	client := &clientStub{}
+	caller := &Caller{client: client, contract: stub}
+
+	calls, err := caller.Call(nil, call1, call2, call3)
+	r.NoError(err)
+	r.Equal(2, sent)
+	r.Equal([]uint64{50_000_000}, client.gas)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.True(calls[1].Outputs.(*boolOutput).Val1)
+	r.False(calls[2].Outputs.(*boolOutput).Val1)
+
+	// a failing call with gas limit fails the batch unless it can fail
+	client.callErr = errors.New("out of gas")
+	_, err = caller.Call(nil, call1, call2)
+	r.EqualError(err, "call at index [1] to "+testAddr1+" (selector 0x76a6e4c2) failed: out of gas")
+
+	calls, err = caller.Call(nil, call1, call2.AllowFailure())
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 1)
+	r.Equal(1, multiErr.Errors[0].Index)
+	r.True(calls[1].Failed)
+
+	// a transport error is not a call failure
+	call2.Failed = false
+	client.callErr = context.Canceled
+	calls, err = caller.Call(nil, call1, call2)
+	r.ErrorIs(err, context.Canceled)
+	r.False(calls[1].Failed)
+
+	// no backend client
+	_, err = (&Caller{contract: stub}).Call(nil, call2)
+	r.ErrorContains(err, "no backend client")
+}
@@ -0,0 +1,69 @@
+package multicall
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChunkHook is notified after each chunk of the chunked methods is done, including the
+// chunks which failed. It can be used for collecting metrics. The hook is called from
+// multiple goroutines by CallConcurrent.
+type ChunkHook interface {
+	OnChunkDone(index int, size int, elapsed time.Duration, err error)
+}
+
+// chunkDone notifies the chunk hook of the caller, if there is any.
+func (caller *Caller) chunkDone(index int, size int, start time.Time, err error) {
+	if caller.chunkHook != nil {
+		caller.chunkHook.OnChunkDone(index, size, time.Since(start), err)
+	}
+}
+
+// ChunkInfo describes a chunk of the chunked methods. Targets is the number of distinct
+// contracts called by the chunk.
+type ChunkInfo struct {
+	Index   int
+	Size    int
+	Targets int
+}
+
+// ChunkTracer is called before each chunk of the chunked methods is dispatched, e.g. for
+// starting a span of a distributed trace. The given context is the context of the call
+// options, which is the parent of the chunk. The returned context is used for the requests
+// of the chunk and the returned function is called with the error of the chunk when it is
+// done, e.g. for ending the span. The tracer is called from multiple goroutines by
+// CallConcurrent.
+type ChunkTracer func(ctx context.Context, chunk ChunkInfo) (context.Context, func(err error))
+
+// traceChunk starts tracing the chunk with the chunk tracer of the caller, if there is any,
+// and returns the options with the returned context and the function which ends the trace.
+func (caller *Caller) traceChunk(opts *bind.CallOpts, index int, calls []*Call) (*bind.CallOpts, func(err error)) {
+	if caller.chunkTracer == nil {
+		return opts, func(error) {}
+	}
+	targets := make(map[common.Address]struct{})
+	for _, call := range calls {
+		targets[callTarget(call)] = struct{}{}
+	}
+	parent, _ := caller.callContext(opts)
+	ctx, end := caller.chunkTracer(parent, ChunkInfo{Index: index, Size: len(calls), Targets: len(targets)})
+	var chunkOpts bind.CallOpts
+	if opts != nil {
+		chunkOpts = *opts
+	}
+	chunkOpts.Context = ctx
+	if end == nil {
+		end = func(error) {}
+	}
+	return &chunkOpts, end
+}
+
+// reportProgress calls the progress callback of the caller, if there is any.
+func (caller *Caller) reportProgress(completed, total int) {
+	if caller.progress != nil {
+		caller.progress(completed, total)
+	}
+}
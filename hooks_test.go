@@ -0,0 +1,164 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+type chunkDone struct {
+	index int
+	size  int
+	err   error
+}
+
+type testChunkHook struct {
+	mu     sync.Mutex
+	chunks []chunkDone
+}
+
+func (hook *testChunkHook) OnChunkDone(index int, size int, elapsed time.Duration, err error) {
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	hook.chunks = append(hook.chunks, chunkDone{index: index, size: size, err: err})
+}
+
+func TestCaller_ChunkHook(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	hook := &testChunkHook{}
+	caller := &Caller{contract: echoStub(), chunkHook: hook}
+
+	_, err = caller.CallChunked(nil, 2, 0, calls...)
+	r.NoError(err)
+	r.Equal([]chunkDone{{index: 0, size: 2}, {index: 1, size: 1}}, hook.chunks)
+
+	hook.chunks = nil
+	_, err = caller.CallConcurrent(nil, 1, 2, calls...)
+	r.NoError(err)
+	r.Len(hook.chunks, 3)
+
+	hook.chunks = nil
+	_, err = caller.CallByteLimited(nil, 1, calls...)
+	r.NoError(err)
+	r.Len(hook.chunks, 3)
+
+	// failed chunks are reported too
+	hook.chunks = nil
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	caller.contract = stub
+	_, err = caller.CallChunked(nil, 2, 0, calls...)
+	r.Error(err)
+	r.Len(hook.chunks, 1)
+	r.ErrorContains(hook.chunks[0].err, "rpc down")
+}
+
+func TestCaller_Progress(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	var progress [][2]int
+	caller := &Caller{contract: echoStub(), progress: func(completed, total int) {
+		progress = append(progress, [2]int{completed, total})
+	}}
+
+	_, err = caller.CallChunked(nil, 2, 0, calls...)
+	r.NoError(err)
+	r.Equal([][2]int{{2, 5}, {4, 5}, {5, 5}}, progress)
+
+	progress = nil
+	_, err = caller.CallByteLimited(nil, 1, calls[:2]...)
+	r.NoError(err)
+	r.Equal([][2]int{{1, 2}, {2, 2}}, progress)
+}
+
+type spanKey struct{}
+
+type tracedChunk struct {
+	chunk  ChunkInfo
+	parent any
+	err    error
+}
+
+func TestCaller_ChunkTracer(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	otherContract, err := NewContract(oneValueABI, testAddr2)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		otherContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+	}
+
+	var (
+		mu     sync.Mutex
+		traced []tracedChunk
+		spans  []any
+	)
+	tracer := func(ctx context.Context, chunk ChunkInfo) (context.Context, func(err error)) {
+		parent := ctx.Value(spanKey{})
+		return context.WithValue(ctx, spanKey{}, chunk.Index), func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			traced = append(traced, tracedChunk{chunk: chunk, parent: parent, err: err})
+		}
+	}
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		mu.Lock()
+		defer mu.Unlock()
+		spans = append(spans, opts.Context.Value(spanKey{}))
+	}
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		if len(calls) == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	}
+	caller := &Caller{contract: stub}
+	WithChunkTracer(tracer)(caller)
+
+	ctx := context.WithValue(context.Background(), spanKey{}, "root")
+	_, err = caller.CallChunked(&bind.CallOpts{Context: ctx}, 2, 0, calls...)
+	r.ErrorContains(err, "call chunk [1] failed")
+	r.Equal([]any{0, 1}, spans)
+	r.Len(traced, 2)
+	r.Equal(ChunkInfo{Index: 0, Size: 2, Targets: 2}, traced[0].chunk)
+	r.Equal("root", traced[0].parent)
+	r.NoError(traced[0].err)
+	r.Equal(ChunkInfo{Index: 1, Size: 1, Targets: 1}, traced[1].chunk)
+	r.ErrorContains(traced[1].err, "failed")
+
+	traced = nil
+	_, err = caller.CallConcurrent(&bind.CallOpts{Context: ctx}, 2, 2, calls...)
+	r.Error(err)
+	r.Len(traced, 2)
+}
@@ -0,0 +1,61 @@
+package multicall
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// callJSON is the JSON form of a call.
+type callJSON struct {
+	Name       string         `json:"name,omitempty"`
+	Target     common.Address `json:"target"`
+	Method     string         `json:"method,omitempty"`
+	Selector   hexutil.Bytes  `json:"selector,omitempty"`
+	CallData   hexutil.Bytes  `json:"callData"`
+	CanFail    bool           `json:"canFail"`
+	Failed     bool           `json:"failed"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+}
+
+// MarshalJSON implements json.Marshaler. The call is encoded with its target, method
+// selector, calldata and raw results.
+func (call *Call) MarshalJSON() ([]byte, error) {
+	if call.Contract == nil {
+		return nil, errors.New("call has no contract")
+	}
+	callData, err := call.Pack()
+	if err != nil {
+		return nil, err
+	}
+	encoded := callJSON{
+		Name:       call.CallName,
+		Target:     call.Contract.Address,
+		Method:     call.Method,
+		CallData:   callData,
+		CanFail:    call.CanFail,
+		Failed:     call.Failed,
+		ReturnData: call.RawReturn,
+	}
+	if len(callData) >= 4 {
+		encoded.Selector = callData[:4]
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The decoded call has no ABI and is made
+// with the calldata like the calls created with NewRawCall.
+func (call *Call) UnmarshalJSON(b []byte) error {
+	var decoded callJSON
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	*call = *NewRawCall(decoded.Target, decoded.CallData, decoded.CanFail)
+	call.CallName = decoded.Name
+	call.Method = decoded.Method
+	call.Failed = decoded.Failed
+	call.setRawReturn(decoded.ReturnData)
+	return nil
+}
@@ -0,0 +1,76 @@
+package multicall
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCall_JSON(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call := testContract.NewCall(new(boolOutput), "testFunc", true).Name("test").AllowFailure()
+	calls, err := (&Caller{contract: echoStub()}).Call(nil, call)
+	r.NoError(err)
+
+	b, err := json.Marshal(calls)
+	r.NoError(err)
+
+	callData, err := call.Pack()
+	r.NoError(err)
+	var encoded []map[string]any
+	r.NoError(json.Unmarshal(b, &encoded))
+	r.Equal("test", encoded[0]["name"])
+	r.Equal("testFunc", encoded[0]["method"])
+	r.Equal(strings.ToLower(testAddr1), encoded[0]["target"])
+	r.Equal("0x"+common.Bytes2Hex(callData[:4]), encoded[0]["selector"])
+	r.Equal(false, encoded[0]["failed"])
+
+	var decoded []*Call
+	r.NoError(json.Unmarshal(b, &decoded))
+	r.Len(decoded, 1)
+	r.Equal(common.HexToAddress(testAddr1), decoded[0].Contract.Address)
+	r.Equal("test", decoded[0].CallName)
+	r.True(decoded[0].CanFail)
+	r.Equal(call.RawReturn, decoded[0].RawReturn)
+	r.False(decoded[0].Empty)
+
+	// decoded calls can be made again with the calldata
+	decodedCallData, err := decoded[0].Pack()
+	r.NoError(err)
+	r.Equal(callData, decodedCallData)
+	_, err = (&Caller{contract: echoStub()}).Call(nil, decoded...)
+	r.NoError(err)
+	r.Equal(call.RawReturn, decoded[0].RawReturn)
+
+	// the empty results are kept in the round trip
+	stub := echoStub()
+	stub.returnData = func(calls []contract_multicall.Multicall3Call3) [][]byte {
+		return [][]byte{{}}
+	}
+	emptyCall := NewRawCall(common.HexToAddress(testAddr2), []byte{0x01, 0x02, 0x03, 0x04}, true)
+	_, err = (&Caller{contract: stub}).Call(nil, emptyCall)
+	r.NoError(err)
+	r.True(emptyCall.Empty)
+	b, err = json.Marshal(emptyCall)
+	r.NoError(err)
+	decodedCall := new(Call)
+	r.NoError(json.Unmarshal(b, decodedCall))
+	r.True(decodedCall.Empty)
+	r.Empty(decodedCall.RawReturn)
+	r.Equal(emptyCall.CanFail, decodedCall.CanFail)
+	r.Equal(emptyCall.Failed, decodedCall.Failed)
+
+	_, err = json.Marshal(testContract.NewCall(new(boolOutput), "testFunc", "not a bool"))
+	r.Error(err)
+
+	_, err = json.Marshal(&Call{Method: "testFunc"})
+	r.ErrorContains(err, "call has no contract")
+}
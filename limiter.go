@@ -0,0 +1,17 @@
+package multicall
+
+import "context"
+
+// Limiter throttles the chunk dispatches of the chunked methods. It is waited on before
+// each chunk is sent. *rate.Limiter from golang.org/x/time/rate implements it.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// wait waits on the limiter of the caller, if there is any.
+func (caller *Caller) wait(ctx context.Context) error {
+	if caller.limiter == nil {
+		return nil
+	}
+	return caller.limiter.Wait(ctx)
+}
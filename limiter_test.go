@@ -0,0 +1,64 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testLimiter struct {
+	mu    sync.Mutex
+	waits int
+	err   error
+}
+
+func (limiter *testLimiter) Wait(ctx context.Context) error {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.waits++
+	if limiter.err != nil {
+		return limiter.err
+	}
+	return ctx.Err()
+}
+
+func TestCaller_Limiter(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	limiter := &testLimiter{}
+	caller := &Caller{contract: echoStub(), limiter: limiter}
+
+	_, err = caller.CallChunked(nil, 2, 0, calls...)
+	r.NoError(err)
+	r.Equal(2, limiter.waits)
+
+	limiter.waits = 0
+	_, err = caller.CallConcurrent(nil, 1, 2, calls...)
+	r.NoError(err)
+	r.Equal(3, limiter.waits)
+
+	limiter.waits = 0
+	_, err = caller.CallByteLimited(nil, 1, calls...)
+	r.NoError(err)
+	r.Equal(3, limiter.waits)
+
+	limiter.waits = 0
+	limiter.err = errors.New("limited")
+	_, err = caller.CallChunked(nil, 2, 0, calls...)
+	r.EqualError(err, "limited")
+	r.Equal(1, limiter.waits)
+
+	_, err = caller.CallConcurrent(nil, 1, 2, calls...)
+	r.EqualError(err, "limited")
+}
@@ -0,0 +1,22 @@
+package multicall
+
+// Logger is the logger used by the caller. Debug messages are about the chunking progress
+// and warnings are about the calls which could not be chunked as expected.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(format string, args ...any) {}
+
+func (nopLogger) Warnf(format string, args ...any) {}
+
+// log returns the logger of the caller or a no-op logger.
+func (caller *Caller) log() Logger {
+	if caller.logger == nil {
+		return nopLogger{}
+	}
+	return caller.logger
+}
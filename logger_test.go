@@ -0,0 +1,63 @@
+package multicall
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testLogger struct {
+	mu       sync.Mutex
+	debugs   []string
+	warnings []string
+}
+
+func (tl *testLogger) Debugf(format string, args ...any) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.debugs = append(tl.debugs, fmt.Sprintf(format, args...))
+}
+
+func (tl *testLogger) Warnf(format string, args ...any) {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+	tl.warnings = append(tl.warnings, fmt.Sprintf(format, args...))
+}
+
+func TestCaller_Logger(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	logger := &testLogger{}
+	caller := &Caller{contract: echoStub(), logger: logger}
+
+	_, err = caller.CallChunked(nil, 2, 0, calls...)
+	r.NoError(err)
+	r.Len(logger.debugs, 3)
+	r.Equal("multicall: making 3 calls in 2 chunks", logger.debugs[0])
+	r.Contains(logger.debugs[1], "chunk [0] with 2 calls")
+	r.Contains(logger.debugs[2], "chunk [1] with 1 calls")
+
+	logger.debugs = nil
+	_, err = caller.TryCallChunked(nil, false, 2, 0, calls...)
+	r.NoError(err)
+	r.Len(logger.debugs, 3)
+
+	_, err = caller.CallGasLimited(nil, 10, calls[0].WithGasEstimate(20))
+	r.NoError(err)
+	r.Len(logger.warnings, 1)
+	r.Contains(logger.warnings[0], "sending alone")
+
+	// no logger
+	_, err = (&Caller{contract: echoStub()}).CallChunked(nil, 2, 0, calls...)
+	r.NoError(err)
+}
@@ -0,0 +1,83 @@
+package multicall
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// minMixedGroupSize is the smallest group of calls which CallMixed sends separately.
+// Splitting off a smaller group costs more in the extra request than it saves.
+const minMixedGroupSize = 10
+
+// CallMixed makes multicalls like Call but sends the calls which do not allow failure with
+// the cheaper aggregate method and the rest with aggregate3. The calls are updated in place
+// and returned in the given order. When either of the groups is smaller than
+// minMixedGroupSize, all calls are sent with aggregate3. An error of the aggregate group
+// is returned as is, like with Aggregate, unless it is a *MultiError of unpack failures.
+// The call errors of both groups are returned in a *MultiError with the indexes in the
+// given calls. The groups are separate multicalls, so they are not atomic, but both are
+// made at the block of opts, or at the latest block which is looked up first when opts
+// has no block number.
+func (caller *Caller) CallMixed(opts *bind.CallOpts, calls ...*Call) ([]*Call, error) {
+	var (
+		strict, failable               []*Call
+		strictIndexes, failableIndexes []int
+	)
+	for i, call := range calls {
+		if call.CanFail {
+			failable = append(failable, call)
+			failableIndexes = append(failableIndexes, i)
+		} else {
+			strict = append(strict, call)
+			strictIndexes = append(strictIndexes, i)
+		}
+	}
+	if len(strict) < minMixedGroupSize || (len(failable) > 0 && len(failable) < minMixedGroupSize) {
+		return caller.Call(opts, calls...)
+	}
+
+	if len(failable) > 0 && (opts == nil || opts.BlockNumber == nil) {
+		blockNumber, err := caller.BlockNumber(opts)
+		if err != nil {
+			return calls, err
+		}
+		var pinnedOpts bind.CallOpts
+		if opts != nil {
+			pinnedOpts = *opts
+		}
+		pinnedOpts.BlockNumber = new(big.Int).SetUint64(blockNumber)
+		opts = &pinnedOpts
+	}
+
+	var multiErr MultiError
+	_, _, err := caller.Aggregate(opts, strict...)
+	if !mergeGroupErrors(&multiErr, err, strictIndexes) {
+		return calls, err
+	}
+	if len(failable) > 0 {
+		_, err = caller.Call(opts, failable...)
+		if !mergeGroupErrors(&multiErr, err, failableIndexes) {
+			return calls, err
+		}
+	}
+	multiErr.sort()
+	return calls, multiErr.errOrNil()
+}
+
+// mergeGroupErrors adds the call errors of a group to multiErr with the indexes of the
+// calls in the whole batch. It returns false if err is another error which fails the
+// whole batch.
+func mergeGroupErrors(multiErr *MultiError, err error, indexes []int) bool {
+	groupErr := (*MultiError)(nil)
+	if !errors.As(err, &groupErr) {
+		return err == nil
+	}
+	for _, callErr := range groupErr.Errors {
+		shifted := *callErr
+		shifted.Index = indexes[callErr.Index]
+		multiErr.Errors = append(multiErr.Errors, &shifted)
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package multicall
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+type aggregateCounter struct {
+	*multicallStub
+	aggregated   []int
+	blockNumbers []*big.Int
+}
+
+func (ac *aggregateCounter) Aggregate(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call) (result struct {
+	BlockNumber *big.Int
+	ReturnData  [][]byte
+}, err error) {
+	ac.aggregated = append(ac.aggregated, len(calls))
+	if opts != nil {
+		ac.blockNumbers = append(ac.blockNumbers, opts.BlockNumber)
+	}
+	return ac.multicallStub.Aggregate(opts, calls)
+}
+
+func TestCaller_CallMixed(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	newCalls := func(strict, failable int) []*Call {
+		var calls []*Call
+		for i := 0; i < strict+failable; i++ {
+			call := testContract.NewCall(new(boolOutput), "testFunc", i%3 == 0)
+			if i%2 == 1 && i/2 < failable {
+				call.AllowFailure()
+			}
+			calls = append(calls, call)
+		}
+		return calls
+	}
+
+	var (
+		aggregated3   []int
+		blockNumbers3 []*big.Int
+	)
+	stub := &aggregateCounter{multicallStub: echoStub()}
+	stub.failures = map[int]bool{1: true} // index in the aggregate3 batch
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		aggregated3 = append(aggregated3, len(calls))
+		return nil
+	}
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		if opts != nil {
+			blockNumbers3 = append(blockNumbers3, opts.BlockNumber)
+		}
+	}
+	caller := &Caller{contract: stub}
+
+	calls, err := caller.CallMixed(nil, newCalls(10, 10)...)
+	r.Len(calls, 20)
+	r.Equal([]int{10}, stub.aggregated)
+	r.Equal([]int{10}, aggregated3)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 1)
+	r.Equal(3, multiErr.Errors[0].Index) // second failable call
+	// both groups are made at the latest block
+	r.Equal([]*big.Int{big.NewInt(testBlockNumber)}, stub.blockNumbers)
+	r.Equal([]*big.Int{big.NewInt(testBlockNumber)}, blockNumbers3)
+	r.True(calls[3].Failed)
+	for i, call := range calls {
+		if !call.Failed {
+			r.Equal(i%3 == 0, call.Outputs.(*boolOutput).Val1)
+		}
+	}
+
+	// the block of the options is kept
+	stub.aggregated, aggregated3 = nil, nil
+	stub.blockNumbers, blockNumbers3 = nil, nil
+	_, err = caller.CallMixed(&bind.CallOpts{BlockNumber: big.NewInt(42)}, newCalls(10, 10)...)
+	r.ErrorAs(err, &multiErr)
+	r.Equal([]*big.Int{big.NewInt(42)}, stub.blockNumbers)
+	r.Equal([]*big.Int{big.NewInt(42)}, blockNumbers3)
+
+	// a tiny group falls back to aggregate3
+	stub.aggregated, aggregated3 = nil, nil
+	stub.failures = nil
+	_, err = caller.CallMixed(nil, newCalls(15, 2)...)
+	r.NoError(err)
+	r.Empty(stub.aggregated)
+	r.Equal([]int{17}, aggregated3)
+
+	// no failable calls
+	stub.aggregated, aggregated3 = nil, nil
+	_, err = caller.CallMixed(nil, newCalls(12, 0)...)
+	r.NoError(err)
+	r.Equal([]int{12}, stub.aggregated)
+	r.Empty(aggregated3)
+
+	// the unpack failures of both groups
+	stub.failures = map[int]bool{1: true}
+	calls = newCalls(10, 10)
+	calls[2].Outputs = new(struct{ Val1 string }) // wrong type in the aggregate group
+	_, err = caller.CallMixed(nil, calls...)
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 2)
+	r.Equal(2, multiErr.Errors[0].Index)
+	r.Error(calls[2].UnpackErr)
+	r.Equal(3, multiErr.Errors[1].Index)
+	r.True(calls[3].Failed)
+	stub.failures = nil
+
+	// the error of the aggregate group
+	calls = newCalls(10, 10)
+	calls[0].Inputs = []any{'a'} // bad input
+	_, err = caller.CallMixed(nil, calls...)
+	r.ErrorContains(err, "failed to pack")
+	r.False(errors.As(err, &multiErr))
+}
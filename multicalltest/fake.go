@@ -0,0 +1,215 @@
+// Package multicalltest provides a fake multicall contract for testing the code which
+// makes calls with a multicall.Caller.
+package multicalltest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+)
+
+// ErrCallFailed is returned by the fake when a call which is not allowed to fail fails,
+// like the revert of the multicall contract.
+var ErrCallFailed = errors.New("execution reverted: Multicall3: call failed")
+
+// Response is the scripted response of a call.
+type Response struct {
+	ReturnData []byte
+	Fail       bool
+}
+
+type callKey struct {
+	target   common.Address
+	callData string
+}
+
+// Fake is a multicall contract which responds to the calls with the scripted responses.
+// The calls which have no response fail. The block info is returned from the fields.
+type Fake struct {
+	BlockNumber *big.Int
+	BlockHash   common.Hash
+	ChainID     *big.Int
+	BaseFee     *big.Int
+	Coinbase    common.Address
+	Difficulty  *big.Int
+	GasLimit    *big.Int
+	Timestamp   *big.Int
+	// Err fails all multicalls when set.
+	Err error
+
+	mu        sync.Mutex
+	responses map[callKey]Response
+	batches   [][]contract_multicall.Multicall3Call3
+}
+
+var _ multicall.MulticallContract = (*Fake)(nil)
+
+// New creates a new fake with some block info.
+func New() *Fake {
+	return &Fake{
+		BlockNumber: big.NewInt(1),
+		ChainID:     big.NewInt(1),
+		BaseFee:     big.NewInt(1),
+		Difficulty:  big.NewInt(0),
+		GasLimit:    big.NewInt(30000000),
+		Timestamp:   big.NewInt(0),
+		responses:   make(map[callKey]Response),
+	}
+}
+
+// Respond scripts the response of the call to the target with given calldata.
+func (fake *Fake) Respond(target common.Address, callData []byte, response Response) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.responses[callKey{target: target, callData: string(callData)}] = response
+}
+
+// Return scripts the call to return given outputs, which are packed with the ABI of the
+// call.
+func (fake *Fake) Return(call *multicall.Call, outputs ...any) error {
+	callData, err := call.Pack()
+	if err != nil {
+		return err
+	}
+	method, ok := call.Contract.ABI.Methods[call.Method]
+	if !ok {
+		return fmt.Errorf("method '%s' not found in abi", call.Method)
+	}
+	returnData, err := method.Outputs.Pack(outputs...)
+	if err != nil {
+		return fmt.Errorf("failed to pack '%s' outputs: %v", call.Method, err)
+	}
+	fake.Respond(call.Contract.Address, callData, Response{ReturnData: returnData})
+	return nil
+}
+
+// Revert scripts the call to fail with given revert data.
+func (fake *Fake) Revert(call *multicall.Call, revertData []byte) error {
+	callData, err := call.Pack()
+	if err != nil {
+		return err
+	}
+	fake.Respond(call.Contract.Address, callData, Response{ReturnData: revertData, Fail: true})
+	return nil
+}
+
+// Batches returns the calls of each multicall made so far.
+func (fake *Fake) Batches() [][]contract_multicall.Multicall3Call3 {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return append([][]contract_multicall.Multicall3Call3(nil), fake.batches...)
+}
+
+func (fake *Fake) Aggregate3(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call3) ([]contract_multicall.Multicall3Result, error) {
+	if fake.Err != nil {
+		return nil, fake.Err
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.batches = append(fake.batches, calls)
+
+	results := make([]contract_multicall.Multicall3Result, len(calls))
+	for i, call := range calls {
+		response, ok := fake.responses[callKey{target: call.Target, callData: string(call.CallData)}]
+		if !ok || response.Fail {
+			if !call.AllowFailure {
+				return nil, ErrCallFailed
+			}
+			results[i] = contract_multicall.Multicall3Result{ReturnData: response.ReturnData}
+			continue
+		}
+		results[i] = contract_multicall.Multicall3Result{Success: true, ReturnData: response.ReturnData}
+	}
+	return results, nil
+}
+
+func (fake *Fake) Aggregate(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call) (result struct {
+	BlockNumber *big.Int
+	ReturnData  [][]byte
+}, err error) {
+	results, err := fake.Aggregate3(opts, toCalls3(calls, false))
+	if err != nil {
+		return result, err
+	}
+	result.BlockNumber = fake.BlockNumber
+	for _, callResult := range results {
+		result.ReturnData = append(result.ReturnData, callResult.ReturnData)
+	}
+	return result, nil
+}
+
+func (fake *Fake) BlockAndAggregate(opts *bind.CallOpts, calls []contract_multicall.Multicall3Call) (struct {
+	BlockNumber *big.Int
+	BlockHash   [32]byte
+	ReturnData  []contract_multicall.Multicall3Result
+}, error) {
+	return fake.TryBlockAndAggregate(opts, true, calls)
+}
+
+func (fake *Fake) TryAggregate(opts *bind.CallOpts, requireSuccess bool, calls []contract_multicall.Multicall3Call) ([]contract_multicall.Multicall3Result, error) {
+	return fake.Aggregate3(opts, toCalls3(calls, !requireSuccess))
+}
+
+func (fake *Fake) TryBlockAndAggregate(opts *bind.CallOpts, requireSuccess bool, calls []contract_multicall.Multicall3Call) (result struct {
+	BlockNumber *big.Int
+	BlockHash   [32]byte
+	ReturnData  []contract_multicall.Multicall3Result
+}, err error) {
+	result.ReturnData, err = fake.TryAggregate(opts, requireSuccess, calls)
+	if err != nil {
+		return result, err
+	}
+	result.BlockNumber = fake.BlockNumber
+	result.BlockHash = fake.BlockHash
+	return result, nil
+}
+
+func (fake *Fake) GetBasefee(opts *bind.CallOpts) (*big.Int, error) {
+	return fake.BaseFee, nil
+}
+
+func (fake *Fake) GetBlockHash(opts *bind.CallOpts, blockNumber *big.Int) ([32]byte, error) {
+	return fake.BlockHash, nil
+}
+
+func (fake *Fake) GetBlockNumber(opts *bind.CallOpts) (*big.Int, error) {
+	return fake.BlockNumber, nil
+}
+
+func (fake *Fake) GetChainId(opts *bind.CallOpts) (*big.Int, error) {
+	return fake.ChainID, nil
+}
+
+func (fake *Fake) GetCurrentBlockCoinbase(opts *bind.CallOpts) (common.Address, error) {
+	return fake.Coinbase, nil
+}
+
+func (fake *Fake) GetCurrentBlockDifficulty(opts *bind.CallOpts) (*big.Int, error) {
+	return fake.Difficulty, nil
+}
+
+func (fake *Fake) GetCurrentBlockGasLimit(opts *bind.CallOpts) (*big.Int, error) {
+	return fake.GasLimit, nil
+}
+
+func (fake *Fake) GetCurrentBlockTimestamp(opts *bind.CallOpts) (*big.Int, error) {
+	return fake.Timestamp, nil
+}
+
+func toCalls3(calls []contract_multicall.Multicall3Call, allowFailure bool) (calls3 []contract_multicall.Multicall3Call3) {
+	for _, call := range calls {
+		calls3 = append(calls3, contract_multicall.Multicall3Call3{
+			Target:       call.Target,
+			AllowFailure: allowFailure,
+			CallData:     call.CallData,
+		})
+	}
+	return
+}
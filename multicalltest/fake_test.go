@@ -0,0 +1,56 @@
+package multicalltest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jbexdp/go-multicall"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{
+		"inputs":[],
+		"name":"totalSupply",
+		"outputs":[{"name":"supply","type":"uint256"}],
+		"stateMutability":"view",
+		"type":"function"
+	}
+]`
+
+type supplyOutput struct {
+	Supply *big.Int
+}
+
+func TestFake(t *testing.T) {
+	r := require.New(t)
+
+	token1, err := multicall.NewContract(testABI, "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	r.NoError(err)
+	token2, err := multicall.NewContract(testABI, "0x64d5192F03bD98dB1De2AA8B4abAC5419eaC32CE")
+	r.NoError(err)
+
+	fake := New()
+	caller := multicall.NewWithContract(fake)
+
+	call1 := token1.NewCall(new(supplyOutput), "totalSupply")
+	call2 := token2.NewCall(new(supplyOutput), "totalSupply").AllowFailure()
+	r.NoError(fake.Return(call1, big.NewInt(1000)))
+	r.NoError(fake.Revert(call2, []byte{0x01}))
+
+	calls, err := caller.Call(nil, call1, call2)
+	r.Error(err) // call2 failed
+	r.Equal(big.NewInt(1000), calls[0].Outputs.(*supplyOutput).Supply)
+	r.True(calls[1].Failed)
+	r.Equal([]byte{0x01}, calls[1].RawReturn)
+	r.Len(fake.Batches(), 1)
+
+	// calls which are not allowed to fail revert the batch
+	_, err = caller.Call(nil, token2.NewCall(new(supplyOutput), "totalSupply"))
+	r.ErrorContains(err, ErrCallFailed.Error())
+
+	fake.BlockNumber = big.NewInt(42)
+	blockNumber, err := caller.BlockNumber(nil)
+	r.NoError(err)
+	r.Equal(uint64(42), blockNumber)
+}
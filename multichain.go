@@ -0,0 +1,100 @@
+package multicall
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// MultiChainCaller makes the same calls on multiple chains.
+type MultiChainCaller struct {
+	callers map[uint64]*Caller
+}
+
+// NewMultiChainCaller creates a new multichain caller from the callers by their chain IDs.
+func NewMultiChainCaller(callers map[uint64]*Caller) *MultiChainCaller {
+	return &MultiChainCaller{callers: callers}
+}
+
+// DialMultiChain dials the Ethereum JSON-RPC API URLs by their chain IDs and creates a
+// multichain caller. The options are used for the caller of each chain. The connections
+// which were already made are closed when dialing any of the chains fails.
+func DialMultiChain(ctx context.Context, rawUrls map[uint64]string, opts ...Option) (*MultiChainCaller, error) {
+	callers := make(map[uint64]*Caller, len(rawUrls))
+	for chainID, rawUrl := range rawUrls {
+		caller, err := Dial(ctx, rawUrl, opts...)
+		if err != nil {
+			NewMultiChainCaller(callers).Close()
+			return nil, fmt.Errorf("failed to dial chain %d: %v", chainID, err)
+		}
+		callers[chainID] = caller
+	}
+	return NewMultiChainCaller(callers), nil
+}
+
+// Caller returns the caller of the chain.
+func (mc *MultiChainCaller) Caller(chainID uint64) (*Caller, bool) {
+	caller, ok := mc.callers[chainID]
+	return caller, ok
+}
+
+// Close closes the callers of all chains like Caller.Close, so the connections of the
+// callers created by dialing are closed.
+func (mc *MultiChainCaller) Close() {
+	for _, caller := range mc.callers {
+		caller.Close()
+	}
+}
+
+// Call makes the calls on each chain in parallel and returns the results by the chain IDs.
+// The calls are copied for each chain. The chains which fail do not stop the rest and the
+// returned error is a ChainErrors with the errors of the failed chains.
+func (mc *MultiChainCaller) Call(opts *bind.CallOpts, calls ...*Call) (map[uint64][]*Call, error) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[uint64][]*Call, len(mc.callers))
+		errs    = make(ChainErrors)
+	)
+	for chainID, caller := range mc.callers {
+		wg.Add(1)
+		go func(chainID uint64, caller *Caller) {
+			defer wg.Done()
+			chainCalls, err := caller.Call(opts, cloneCalls(calls)...)
+			mu.Lock()
+			defer mu.Unlock()
+			results[chainID] = chainCalls
+			if err != nil {
+				errs[chainID] = err
+			}
+		}(chainID, caller)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
+
+// ChainErrors are the errors of the chains which failed by their chain IDs.
+type ChainErrors map[uint64]error
+
+// Error implements the error interface.
+func (errs ChainErrors) Error() string {
+	chainIDs := make([]uint64, 0, len(errs))
+	for chainID := range errs {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+
+	msgs := make([]string, 0, len(errs))
+	for _, chainID := range chainIDs {
+		msgs = append(msgs, fmt.Sprintf("chain %d: %v", chainID, errs[chainID]))
+	}
+	return fmt.Sprintf("%d chain(s) failed: %s", len(errs), strings.Join(msgs, "; "))
+}
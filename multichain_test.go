@@ -0,0 +1,72 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiChainCaller_Call(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	failingStub := echoStub()
+	failingStub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	mc := NewMultiChainCaller(map[uint64]*Caller{
+		1:   {contract: echoStub()},
+		137: {contract: echoStub()},
+		250: {contract: failingStub},
+	})
+
+	call := testContract.NewCall(new(boolOutput), "testFunc", true)
+	results, err := mc.Call(nil, call)
+	r.EqualError(err, "1 chain(s) failed: chain 250: multicall failed: rpc down")
+	var chainErrs ChainErrors
+	r.ErrorAs(err, &chainErrs)
+	r.Len(chainErrs, 1)
+
+	r.Len(results, 3)
+	r.True(results[1][0].Outputs.(*boolOutput).Val1)
+	r.True(results[137][0].Outputs.(*boolOutput).Val1)
+	r.NotSame(results[1][0], results[137][0])
+	r.False(call.Outputs.(*boolOutput).Val1)
+
+	_, ok := mc.Caller(137)
+	r.True(ok)
+	_, ok = mc.Caller(10)
+	r.False(ok)
+}
+
+func TestDialMultiChain(t *testing.T) {
+	r := require.New(t)
+
+	_, err := DialMultiChain(context.Background(), map[uint64]string{1: "ftp://invalid"})
+	r.ErrorContains(err, "failed to dial chain 1")
+
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", ethService{}))
+	defer server.Stop()
+	wsServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer wsServer.Close()
+	wsUrl := "ws" + strings.TrimPrefix(wsServer.URL, "http")
+
+	mc, err := DialMultiChain(context.Background(), map[uint64]string{1: wsUrl, 137: wsUrl})
+	r.NoError(err)
+	mc.Close()
+	for _, chainID := range []uint64{1, 137} {
+		caller, ok := mc.Caller(chainID)
+		r.True(ok)
+		_, err = caller.client.(chainIDReader).ChainID(context.Background())
+		r.ErrorIs(err, rpc.ErrClientQuit)
+	}
+}
@@ -0,0 +1,150 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// endpointBackoff is how long an endpoint which failed is skipped.
+const endpointBackoff = 30 * time.Second
+
+// NewMultiEndpoint dials the Ethereum JSON-RPC API URLs and creates a caller which
+// distributes the requests across the endpoints in turn, so the chunks of the chunked
+// methods are spread across them. A request which fails with a connection or a rate limit
+// error is retried with the next endpoint, and the failed endpoint is skipped for a while
+// unless all endpoints have failed. The caller cannot send transactions.
+func NewMultiEndpoint(ctx context.Context, rawUrls []string, opts ...Option) (*Caller, error) {
+	if len(rawUrls) == 0 {
+		return nil, errors.New("no endpoints given")
+	}
+	rpcClients := make([]*rpc.Client, 0, len(rawUrls))
+	for _, rawUrl := range rawUrls {
+		rpcClient, err := rpc.DialContext(ctx, rawUrl)
+		if err != nil {
+			for _, dialed := range rpcClients {
+				dialed.Close()
+			}
+			return nil, fmt.Errorf("failed to dial %s: %v", rawUrl, err)
+		}
+		rpcClients = append(rpcClients, rpcClient)
+	}
+	return newMultiEndpoint(ctx, rpcClients, opts...)
+}
+
+func newMultiEndpoint(ctx context.Context, rpcClients []*rpc.Client, opts ...Option) (*Caller, error) {
+	client := &multiEndpointClient{now: time.Now}
+	for _, rpcClient := range rpcClients {
+		client.endpoints = append(client.endpoints, &endpoint{rpc: rpcClient})
+	}
+	caller, err := NewContext(ctx, client, opts...)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	caller.rpc = client
+	caller.closer = closeOnce(client.Close)
+	return caller, nil
+}
+
+// multiEndpointClient is a backend client which makes the requests with multiple
+// endpoints in turn.
+type multiEndpointClient struct {
+	endpoints []*endpoint
+	now       func() time.Time
+
+	mu   sync.Mutex
+	next int
+}
+
+type endpoint struct {
+	rpc      *rpc.Client
+	failedAt time.Time
+}
+
+func (mc *multiEndpointClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = mc.do(ctx, func(rpcClient *rpc.Client) (err error) {
+		code, err = ethclient.NewClient(rpcClient).CodeAt(ctx, contract, blockNumber)
+		return
+	})
+	return
+}
+
+func (mc *multiEndpointClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (returnData []byte, err error) {
+	err = mc.do(ctx, func(rpcClient *rpc.Client) (err error) {
+		returnData, err = ethclient.NewClient(rpcClient).CallContract(ctx, call, blockNumber)
+		return
+	})
+	return
+}
+
+func (mc *multiEndpointClient) ChainID(ctx context.Context) (chainID *big.Int, err error) {
+	err = mc.do(ctx, func(rpcClient *rpc.Client) (err error) {
+		chainID, err = ethclient.NewClient(rpcClient).ChainID(ctx)
+		return
+	})
+	return
+}
+
+func (mc *multiEndpointClient) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	return mc.do(ctx, func(rpcClient *rpc.Client) error {
+		return rpcClient.CallContext(ctx, result, method, args...)
+	})
+}
+
+// do makes the request with the next healthy endpoint and fails over to the other
+// endpoints on connection and rate limit errors.
+func (mc *multiEndpointClient) do(ctx context.Context, request func(rpcClient *rpc.Client) error) error {
+	var err error
+	for _, endpoint := range mc.order() {
+		err = request(endpoint.rpc)
+		if err == nil || ctx.Err() != nil || !(isConnectionError(err) || IsRateLimitError(err)) {
+			return err
+		}
+		mc.fail(endpoint)
+	}
+	return err
+}
+
+// order returns the endpoints to try a request with, starting from the next endpoint in
+// turn. The endpoints which failed recently are tried last.
+func (mc *multiEndpointClient) order() []*endpoint {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	start := mc.next
+	mc.next = (mc.next + 1) % len(mc.endpoints)
+
+	now := mc.now()
+	healthy := make([]*endpoint, 0, len(mc.endpoints))
+	var failed []*endpoint
+	for i := range mc.endpoints {
+		endpoint := mc.endpoints[(start+i)%len(mc.endpoints)]
+		if !endpoint.failedAt.IsZero() && now.Sub(endpoint.failedAt) < endpointBackoff {
+			failed = append(failed, endpoint)
+			continue
+		}
+		healthy = append(healthy, endpoint)
+	}
+	return append(healthy, failed...)
+}
+
+// Close closes the connections of all endpoints.
+func (mc *multiEndpointClient) Close() {
+	for _, endpoint := range mc.endpoints {
+		endpoint.rpc.Close()
+	}
+}
+
+func (mc *multiEndpointClient) fail(endpoint *endpoint) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	endpoint.failedAt = mc.now()
+}
@@ -0,0 +1,96 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type countingEthService struct {
+	ethService
+	requests *int64
+}
+
+func (service countingEthService) ChainId() *hexutil.Big {
+	atomic.AddInt64(service.requests, 1)
+	return service.ethService.ChainId()
+}
+
+func (service countingEthService) GetCode(addr common.Address, block string) (hexutil.Bytes, error) {
+	atomic.AddInt64(service.requests, 1)
+	return service.ethService.GetCode(addr, block)
+}
+
+func TestMultiEndpointClient(t *testing.T) {
+	r := require.New(t)
+
+	requests := make([]int64, 3)
+	var rpcClients []*rpc.Client
+	for i := range requests {
+		server := rpc.NewServer()
+		r.NoError(server.RegisterName("eth", countingEthService{requests: &requests[i]}))
+		defer server.Stop()
+		rpcClients = append(rpcClients, rpc.DialInProc(server))
+	}
+
+	ctx := context.Background()
+	caller, err := newMultiEndpoint(ctx, rpcClients, WithAddress(DefaultAddress))
+	r.NoError(err)
+	client := caller.rpc.(*multiEndpointClient)
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		chainID, err := client.ChainID(ctx)
+		r.NoError(err)
+		r.Equal(big.NewInt(testChainID), chainID)
+	}
+	r.Equal([]int64{1, 1, 1}, requests)
+
+	// the second endpoint goes down
+	rpcClients[1].Close()
+	for i := 0; i < 6; i++ {
+		_, err := client.ChainID(ctx)
+		r.NoError(err)
+	}
+	r.Equal(int64(1), requests[1])
+	r.Equal(int64(8), requests[0]+requests[2])
+	r.Equal(now, client.endpoints[1].failedAt)
+
+	// errors from the node are not failed over
+	_, err = client.CodeAt(ctx, common.HexToAddress(testAddr1), nil)
+	r.ErrorContains(err, "execution reverted")
+	r.Equal(int64(9), requests[0]+requests[2])
+
+	// the failed endpoint is tried again after the backoff
+	now = now.Add(endpointBackoff)
+	for i := 0; i < 3; i++ {
+		_, err := client.ChainID(ctx)
+		r.NoError(err)
+	}
+	r.Equal(now, client.endpoints[1].failedAt)
+
+	// all endpoints are down
+	for _, rpcClient := range rpcClients {
+		rpcClient.Close()
+	}
+	_, err = client.ChainID(ctx)
+	r.ErrorIs(err, rpc.ErrClientQuit)
+}
+
+func TestNewMultiEndpoint(t *testing.T) {
+	r := require.New(t)
+
+	_, err := NewMultiEndpoint(context.Background(), nil)
+	r.EqualError(err, "no endpoints given")
+
+	_, err = NewMultiEndpoint(context.Background(), []string{"unsupported://localhost"})
+	r.ErrorContains(err, "failed to dial unsupported://localhost")
+}
@@ -0,0 +1,188 @@
+package multicall
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Option configures a Caller.
+type Option func(*Caller)
+
+// WithAddress sets the multicall contract address. DefaultAddress is used by default,
+// unless the chain of the client is in ChainDefaults.
+func WithAddress(addr string) Option {
+	return func(caller *Caller) {
+		caller.address = common.HexToAddress(addr)
+		caller.addressSet = true
+	}
+}
+
+// WithDefaultChunkSize sets the chunk size used by the helpers which chunk calls
+// internally, like EthBalances. A chunk size which is not positive is the default of 1000.
+func WithDefaultChunkSize(chunkSize int) Option {
+	return func(caller *Caller) {
+		caller.chunkSize = chunkSize
+	}
+}
+
+// WithDefaultGasEstimate sets the gas estimate used by CallGasLimited for the calls which
+// have no GasEstimate set. An estimate of zero is the default of 100000.
+func WithDefaultGasEstimate(gas uint64) Option {
+	return func(caller *Caller) {
+		caller.defaultGas = gas
+	}
+}
+
+// WithCooldown sets the cooldown between chunks used by the helpers which chunk calls
+// internally, like EthBalances.
+func WithCooldown(cooldown time.Duration) Option {
+	return func(caller *Caller) {
+		caller.cooldown = cooldown
+	}
+}
+
+// WithStrict makes the caller strict. See Caller.Strict.
+func WithStrict() Option {
+	return func(caller *Caller) {
+		caller.strict = true
+	}
+}
+
+// WithLogger sets the logger. Nothing is logged by default.
+func WithLogger(logger Logger) Option {
+	return func(caller *Caller) {
+		caller.logger = logger
+	}
+}
+
+// WithChunkHook sets the hook which is notified after each chunk of the chunked methods.
+func WithChunkHook(hook ChunkHook) Option {
+	return func(caller *Caller) {
+		caller.chunkHook = hook
+	}
+}
+
+// WithLimiter sets the limiter which is waited on before each chunk of the chunked
+// methods. The limiter is respected in addition to the cooldown.
+func WithLimiter(limiter Limiter) Option {
+	return func(caller *Caller) {
+		caller.limiter = limiter
+	}
+}
+
+// WithProgress sets the callback which is called after each chunk of the sequential
+// chunked methods with the number of processed calls and the total number of calls.
+// The callback is called from the goroutine which makes the calls. CallConcurrent does
+// not report progress.
+func WithProgress(progress func(completed, total int)) Option {
+	return func(caller *Caller) {
+		caller.progress = progress
+	}
+}
+
+// WithChunkTimeout sets the timeout of each chunk of the chunked methods, which applies
+// in addition to the context of the call options. With CallChunkedRetry, a chunk which
+// times out is retried.
+func WithChunkTimeout(timeout time.Duration) Option {
+	return func(caller *Caller) {
+		caller.chunkTimeout = timeout
+	}
+}
+
+// WithAdaptiveChunkSizes sets the minimum and maximum chunk sizes used by CallAdaptive.
+func WithAdaptiveChunkSizes(minChunkSize, maxChunkSize int) Option {
+	return func(caller *Caller) {
+		caller.minChunkSize = minChunkSize
+		caller.maxChunkSize = maxChunkSize
+	}
+}
+
+// WithCache sets the cache for the results of the calls pinned to a block.
+func WithCache(cache Cache) Option {
+	return func(caller *Caller) {
+		caller.cache = cache
+	}
+}
+
+// WithSplitOnSizeError makes CallChunked split a chunk in halves recursively when the
+// multicall fails with a size error, until the halves have at most floor calls. The size
+// errors are detected with IsSizeError unless another predicate is given.
+func WithSplitOnSizeError(floor int, isSizeError func(err error) bool) Option {
+	return func(caller *Caller) {
+		caller.splitFloor = floor
+		caller.isSizeError = isSizeError
+	}
+}
+
+// WithErrorABIs registers ABIs whose custom errors are used by Caller.DecodeRevert to
+// decode the return data of failed calls, in addition to the errors of the call's own
+// contract ABI.
+func WithErrorABIs(abis ...*abi.ABI) Option {
+	return func(caller *Caller) {
+		caller.errorABIs = append(caller.errorABIs, abis...)
+	}
+}
+
+// WithAdaptiveCooldown makes the chunked methods back off when the requests are rate
+// limited. After each rate limit error, the extra cooldown added between chunks is
+// multiplied by the factor, starting from the step and up to maxCooldown, and it is
+// decreased by the step after each chunk which succeeds. The rate limit errors are
+// detected with IsRateLimitError unless another classifier is given. The extra cooldown
+// is shared by the chunked calls made concurrently with the caller.
+func WithAdaptiveCooldown(step time.Duration, factor float64, maxCooldown time.Duration, isRateLimited func(err error) bool) Option {
+	return func(caller *Caller) {
+		if isRateLimited == nil {
+			isRateLimited = IsRateLimitError
+		}
+		caller.backpressure = &backpressure{
+			step:          step,
+			factor:        factor,
+			maxCooldown:   maxCooldown,
+			isRateLimited: isRateLimited,
+		}
+	}
+}
+
+// WithMaxCallsPerAggregate sets the largest number of calls which Call makes with a single
+// multicall, since the providers often fail or time out on very large multicalls. Call
+// returns ErrTooManyCalls for more calls, which should rather be made with CallChunked.
+// The limit is 10000 calls by default, and a negative limit disables the check.
+func WithMaxCallsPerAggregate(maxCalls int) Option {
+	return func(caller *Caller) {
+		caller.maxCalls = maxCalls
+	}
+}
+
+// WithDefaultContext sets the context which is used for the calls whose options have no
+// context, instead of context.Background. A context with a deadline bounds all calls made
+// with the caller, so it is mostly useful with a cancellable context of the application
+// lifetime.
+func WithDefaultContext(ctx context.Context) Option {
+	return func(caller *Caller) {
+		caller.defaultCtx = ctx
+	}
+}
+
+// WithCircuitBreaker makes the sequential chunked methods, like CallChunked, continue
+// after a failed chunk instead of stopping at the first one. The calls of a failed chunk
+// are returned unchanged and their errors in the *MultiError are the *ChunkError of
+// the chunk. After maxConsecutiveFailures chunks fail in a row, the method stops early and
+// returns the calls made so far with an error which matches ErrCircuitOpen, wraps the last
+// *ChunkError and also matches the *MultiError of all calls made so far. The circuit
+// breaker is disabled by default.
+func WithCircuitBreaker(maxConsecutiveFailures int) Option {
+	return func(caller *Caller) {
+		caller.maxFailures = maxConsecutiveFailures
+	}
+}
+
+// WithChunkTracer sets the tracer which is called around each chunk of the chunked methods
+// which make multicalls, e.g. for wrapping the chunks in the spans of a distributed trace.
+func WithChunkTracer(tracer ChunkTracer) Option {
+	return func(caller *Caller) {
+		caller.chunkTracer = tracer
+	}
+}
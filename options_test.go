@@ -0,0 +1,74 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Options(t *testing.T) {
+	r := require.New(t)
+
+	caller, err := New(nil)
+	r.NoError(err)
+	r.Equal(common.HexToAddress(DefaultAddress), caller.Address())
+	r.Equal(defaultChunkSize, caller.chunkSize)
+	r.Zero(caller.cooldown)
+	r.False(caller.strict)
+
+	caller, err = New(nil, WithAddress(testAddr1), WithDefaultChunkSize(500), WithCooldown(200*time.Millisecond), WithStrict())
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr1), caller.Address())
+	r.Equal(500, caller.chunkSize)
+	r.Equal(200*time.Millisecond, caller.cooldown)
+	r.True(caller.strict)
+
+	logger := &testLogger{}
+	caller, err = New(nil, WithLogger(logger))
+	r.NoError(err)
+	r.Same(logger, caller.log())
+}
+
+func TestNewContext(t *testing.T) {
+	r := require.New(t)
+
+	caller, err := NewContext(context.Background(), nil, WithAddress(testAddr1))
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr1), caller.Address())
+
+	// the context is used for the chain id lookup of ChainDefaults
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", ethService{}))
+	defer server.Stop()
+	client := ethclient.NewClient(rpc.DialInProc(server))
+
+	ChainDefaults[testChainID] = testAddr2
+	defer delete(ChainDefaults, testChainID)
+
+	caller, err = NewContext(context.Background(), client)
+	r.NoError(err)
+	r.Equal(common.HexToAddress(testAddr2), caller.Address())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewContext(ctx, &chainIDStub{clientStub: &clientStub{}})
+	r.ErrorContains(err, "failed to get chain id")
+}
+
+// chainIDStub fails the chain id lookup when the context is done.
+type chainIDStub struct {
+	*clientStub
+}
+
+func (cs *chainIDStub) ChainID(ctx context.Context) (*big.Int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return big.NewInt(testChainID), nil
+}
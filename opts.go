@@ -0,0 +1,53 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OptsBuilder builds the call options of the calls, see Opts.
+type OptsBuilder struct {
+	opts bind.CallOpts
+}
+
+// Opts starts building call options, e.g.
+//
+//	multicall.Opts().At(block).From(addr).Context(ctx).Build()
+//
+// The options of the latest block with no context are built by default.
+func Opts() *OptsBuilder {
+	return &OptsBuilder{}
+}
+
+// At sets the block number to make the calls at. A nil block number is the latest block.
+func (builder *OptsBuilder) At(blockNumber *big.Int) *OptsBuilder {
+	builder.opts.BlockNumber = blockNumber
+	return builder
+}
+
+// AsOf sets whether the calls are made on the pending state.
+func (builder *OptsBuilder) AsOf(pending bool) *OptsBuilder {
+	builder.opts.Pending = pending
+	return builder
+}
+
+// From sets the sender of the calls.
+func (builder *OptsBuilder) From(addr common.Address) *OptsBuilder {
+	builder.opts.From = addr
+	return builder
+}
+
+// Context sets the context of the calls.
+func (builder *OptsBuilder) Context(ctx context.Context) *OptsBuilder {
+	builder.opts.Context = ctx
+	return builder
+}
+
+// Build returns new call options, so the builder can be reused.
+func (builder *OptsBuilder) Build() *bind.CallOpts {
+	opts := builder.opts
+	return &opts
+}
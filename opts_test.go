@@ -0,0 +1,32 @@
+package multicall
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpts(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(&bind.CallOpts{}, Opts().Build())
+
+	ctx := context.Background()
+	builder := Opts().At(big.NewInt(testBlockNumber)).From(common.HexToAddress(testAddr1)).Context(ctx)
+	opts := builder.Build()
+	r.Equal(&bind.CallOpts{
+		BlockNumber: big.NewInt(testBlockNumber),
+		From:        common.HexToAddress(testAddr1),
+		Context:     ctx,
+	}, opts)
+
+	pending := builder.At(nil).AsOf(true).Build()
+	r.True(pending.Pending)
+	r.Nil(pending.BlockNumber)
+	r.Equal(big.NewInt(testBlockNumber), opts.BlockNumber)
+	r.False(opts.Pending)
+}
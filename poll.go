@@ -0,0 +1,87 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// Poll is like Subscribe but polls the current block number at each interval and makes
+// the calls whenever the block number advances by at least everyNBlocks. A poll is skipped
+// while the previous calls are still being made. The errors are sent like with Subscribe.
+// Both channels are closed when the context is cancelled, or after the error if the
+// interval is not positive or everyNBlocks is zero.
+func (caller *Caller) Poll(ctx context.Context, interval time.Duration, everyNBlocks uint64, calls []*Call) (<-chan []*Call, <-chan error) {
+	results := make(chan []*Call)
+	errs := make(chan error)
+
+	var err error
+	switch {
+	case interval <= 0:
+		err = fmt.Errorf("invalid poll interval %v", interval)
+	case everyNBlocks == 0:
+		err = errors.New("everyNBlocks must be greater than zero")
+	}
+	if err != nil {
+		go func() {
+			defer close(results)
+			defer close(errs)
+			send(ctx, errs, err)
+		}()
+		return results, errs
+	}
+
+	go func() {
+		var wg sync.WaitGroup
+		defer close(results)
+		defer close(errs)
+		defer wg.Wait()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var (
+			inFlight  atomic.Bool
+			refreshed bool
+			lastBlock uint64
+		)
+		for {
+			if !inFlight.Load() {
+				blockNumber, err := caller.BlockNumber(&bind.CallOpts{Context: ctx})
+				if err != nil {
+					send(ctx, errs, err)
+				} else if !refreshed || blockNumber >= lastBlock+everyNBlocks {
+					refreshed, lastBlock = true, blockNumber
+					inFlight.Store(true)
+					wg.Add(1)
+					go func(blockNumber uint64) {
+						defer wg.Done()
+						defer inFlight.Store(false)
+						blockCalls := cloneCalls(calls)
+						opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)}
+						if _, _, _, err := caller.BlockAndAggregate(opts, blockCalls...); err != nil {
+							send(ctx, errs, fmt.Errorf("block %d: %w", blockNumber, err))
+							if !errors.As(err, new(*MultiError)) {
+								return
+							}
+						}
+						send(ctx, results, blockCalls)
+					}(blockNumber)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return results, errs
+}
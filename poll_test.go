@@ -0,0 +1,113 @@
+package multicall
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_Poll(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var (
+		block        atomic.Uint64
+		mu           sync.Mutex
+		blockNumbers []uint64
+		active       atomic.Int32
+		overlapped   atomic.Bool
+	)
+	stub := echoStub()
+	// the chain advances one block at each poll
+	stub.blockNumber = func() uint64 {
+		return block.Add(1)
+	}
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		mu.Lock()
+		defer mu.Unlock()
+		blockNumbers = append(blockNumbers, opts.BlockNumber.Uint64())
+	}
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		if active.Add(1) > 1 {
+			overlapped.Store(true)
+		}
+		defer active.Add(-1)
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	caller := &Caller{contract: stub}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, errs := caller.Poll(ctx, time.Millisecond, 3, []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+	})
+	for i := 0; i < 3; i++ {
+		calls := <-results
+		r.True(calls[0].Outputs.(*boolOutput).Val1)
+	}
+	cancel()
+	for range results {
+	}
+	for range errs {
+	}
+
+	r.False(overlapped.Load())
+	mu.Lock()
+	defer mu.Unlock()
+	r.Equal(uint64(1), blockNumbers[0])
+	for i := 1; i < len(blockNumbers); i++ {
+		r.GreaterOrEqual(blockNumbers[i], blockNumbers[i-1]+3)
+	}
+}
+
+func TestCaller_PollInvalid(t *testing.T) {
+	r := require.New(t)
+
+	caller := &Caller{contract: echoStub()}
+	for _, tc := range []struct {
+		interval     time.Duration
+		everyNBlocks uint64
+		err          string
+	}{
+		{0, 1, "invalid poll interval 0s"},
+		{-time.Second, 1, "invalid poll interval -1s"},
+		{time.Millisecond, 0, "everyNBlocks must be greater than zero"},
+	} {
+		results, errs := caller.Poll(context.Background(), tc.interval, tc.everyNBlocks, nil)
+		r.EqualError(<-errs, tc.err)
+		_, ok := <-results
+		r.False(ok)
+		_, ok = <-errs
+		r.False(ok)
+	}
+}
+
+func TestCaller_PollUnpackError(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	caller := &Caller{contract: echoStub()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, errs := caller.Poll(ctx, time.Millisecond, 1, []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(struct{ Val1 string }), "testFunc", true), // wrong type
+	})
+
+	var multiErr *MultiError
+	r.ErrorAs(<-errs, &multiErr)
+	r.Equal(1, multiErr.Errors[0].Index)
+	calls := <-results
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.Error(calls[1].UnpackErr)
+}
@@ -0,0 +1,140 @@
+// Package poolhelpers reads the state of Uniswap V3 style pools with a multicall.Caller.
+package poolhelpers
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall"
+)
+
+// poolABI is the part of the Uniswap V3 pool ABI used by the helpers.
+const poolABI = `[
+	{
+		"inputs": [],
+		"name": "slot0",
+		"outputs": [
+			{"name": "sqrtPriceX96", "type": "uint160"},
+			{"name": "tick", "type": "int24"},
+			{"name": "observationIndex", "type": "uint16"},
+			{"name": "observationCardinality", "type": "uint16"},
+			{"name": "observationCardinalityNext", "type": "uint16"},
+			{"name": "feeProtocol", "type": "uint8"},
+			{"name": "unlocked", "type": "bool"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "liquidity",
+		"outputs": [{"name": "", "type": "uint128"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "token0",
+		"outputs": [{"name": "", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "token1",
+		"outputs": [{"name": "", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+type slot0Output struct {
+	SqrtPriceX96               *big.Int
+	Tick                       *big.Int
+	ObservationIndex           uint16
+	ObservationCardinality     uint16
+	ObservationCardinalityNext uint16
+	FeeProtocol                uint8
+	Unlocked                   bool
+}
+
+type liquidityOutput struct {
+	Liquidity *big.Int
+}
+
+type tokenOutput struct {
+	Token common.Address
+}
+
+// PoolState is the state of a pool. Failed is set when any of the calls to the pool
+// failed, e.g. when the address is not a pool, and the other fields are then not set.
+type PoolState struct {
+	Pool         common.Address
+	Failed       bool
+	SqrtPriceX96 *big.Int
+	Tick         *big.Int
+	FeeProtocol  uint8
+	Unlocked     bool
+	Liquidity    *big.Int
+	Token0       common.Address
+	Token1       common.Address
+}
+
+// callsPerPool is the number of calls made for each pool.
+const callsPerPool = 4
+
+// PoolStates reads slot0, liquidity, token0 and token1 of the pools with multicalls of
+// the chunk size and cooldown of the caller, and the calls of a pool are kept in the same
+// chunk. The states are returned in the order of the pools. The calls are allowed to
+// fail, so the pools whose calls fail are marked failed instead of failing the others.
+func PoolStates(caller *multicall.Caller, opts *bind.CallOpts, pools []common.Address) ([]PoolState, error) {
+	parsedABI, err := multicall.ParseABI(poolABI)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]*multicall.Call, 0, len(pools)*callsPerPool)
+	for _, pool := range pools {
+		contract := &multicall.Contract{ABI: parsedABI, Address: pool}
+		calls = append(calls,
+			contract.NewCall(new(slot0Output), "slot0").AllowFailure(),
+			contract.NewCall(new(liquidityOutput), "liquidity").AllowFailure(),
+			contract.NewCall(new(tokenOutput), "token0").AllowFailure(),
+			contract.NewCall(new(tokenOutput), "token1").AllowFailure(),
+		)
+	}
+
+	chunkSize := caller.ChunkSize() / callsPerPool * callsPerPool
+	if chunkSize == 0 {
+		chunkSize = callsPerPool
+	}
+	calls, err = caller.CallChunked(opts, chunkSize, caller.Cooldown(), calls...)
+	if err != nil && !errors.As(err, new(*multicall.MultiError)) {
+		return nil, err
+	}
+
+	states := make([]PoolState, len(pools))
+	for i, pool := range pools {
+		states[i].Pool = pool
+		poolCalls := calls[i*callsPerPool : (i+1)*callsPerPool]
+		for _, call := range poolCalls {
+			if call.Failed || call.UnpackErr != nil {
+				states[i].Failed = true
+			}
+		}
+		if states[i].Failed {
+			continue
+		}
+		slot0 := poolCalls[0].Outputs.(*slot0Output)
+		states[i].SqrtPriceX96 = slot0.SqrtPriceX96
+		states[i].Tick = slot0.Tick
+		states[i].FeeProtocol = slot0.FeeProtocol
+		states[i].Unlocked = slot0.Unlocked
+		states[i].Liquidity = poolCalls[1].Outputs.(*liquidityOutput).Liquidity
+		states[i].Token0 = poolCalls[2].Outputs.(*tokenOutput).Token
+		states[i].Token1 = poolCalls[3].Outputs.(*tokenOutput).Token
+	}
+	return states, nil
+}
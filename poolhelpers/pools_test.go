@@ -0,0 +1,67 @@
+package poolhelpers
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall"
+	"github.com/jbexdp/go-multicall/multicalltest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolStates(t *testing.T) {
+	r := require.New(t)
+
+	pool := common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+	notPool := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	token0 := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	token1 := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	parsedABI, err := multicall.ParseABI(poolABI)
+	r.NoError(err)
+	contract := &multicall.Contract{ABI: parsedABI, Address: pool}
+
+	fake := multicalltest.New()
+	r.NoError(fake.Return(contract.NewCall(nil, "slot0"), big.NewInt(1000), big.NewInt(-5), uint16(1), uint16(2), uint16(3), uint8(0), true))
+	r.NoError(fake.Return(contract.NewCall(nil, "liquidity"), big.NewInt(42)))
+	r.NoError(fake.Return(contract.NewCall(nil, "token0"), token0))
+	r.NoError(fake.Return(contract.NewCall(nil, "token1"), token1))
+	caller := multicall.NewWithContract(fake)
+
+	states, err := PoolStates(caller, nil, []common.Address{pool, notPool})
+	r.NoError(err)
+	r.Equal([]PoolState{
+		{
+			Pool:         pool,
+			SqrtPriceX96: big.NewInt(1000),
+			Tick:         big.NewInt(-5),
+			Unlocked:     true,
+			Liquidity:    big.NewInt(42),
+			Token0:       token0,
+			Token1:       token1,
+		},
+		{Pool: notPool, Failed: true},
+	}, states)
+	r.Len(fake.Batches(), 1)
+
+	// more pools than a multicall can hold
+	caller = multicall.NewWithContract(fake, multicall.WithDefaultChunkSize(6), multicall.WithMaxCallsPerAggregate(6))
+	states, err = PoolStates(caller, nil, []common.Address{pool, notPool, pool})
+	r.NoError(err)
+	r.Len(states, 3)
+	r.False(states[0].Failed)
+	r.True(states[1].Failed)
+	r.False(states[2].Failed)
+	r.Equal(big.NewInt(42), states[2].Liquidity)
+	batches := fake.Batches()[1:]
+	r.Len(batches, 3)
+	for _, batch := range batches {
+		r.Len(batch, callsPerPool)
+	}
+
+	fake.Err = errors.New("rpc down")
+	_, err = PoolStates(caller, nil, []common.Address{pool})
+	r.ErrorContains(err, "rpc down")
+}
@@ -0,0 +1,113 @@
+package multicall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+)
+
+// OverrideAccount specifies the state of an account to be overridden in an eth_call.
+type OverrideAccount struct {
+	// Nonce is only overridden when non-zero.
+	Nonce uint64
+	// Code is only overridden when non-nil, so an empty slice clears the code.
+	Code    []byte
+	Balance *big.Int
+	// State replaces the whole storage when non-nil.
+	State map[common.Hash]common.Hash
+	// StateDiff overrides individual storage slots.
+	StateDiff map[common.Hash]common.Hash
+}
+
+// MarshalJSON implements json.Marshaler.
+func (account OverrideAccount) MarshalJSON() ([]byte, error) {
+	type override struct {
+		Nonce     hexutil.Uint64              `json:"nonce,omitempty"`
+		Code      *hexutil.Bytes              `json:"code,omitempty"`
+		Balance   *hexutil.Big                `json:"balance,omitempty"`
+		State     map[common.Hash]common.Hash `json:"state,omitempty"`
+		StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+	}
+	output := override{
+		Nonce:     hexutil.Uint64(account.Nonce),
+		Balance:   (*hexutil.Big)(account.Balance),
+		State:     account.State,
+		StateDiff: account.StateDiff,
+	}
+	if account.Code != nil {
+		code := hexutil.Bytes(account.Code)
+		output.Code = &code
+	}
+	return json.Marshal(output)
+}
+
+// rpcCaller is the subset of *rpc.Client used for the raw JSON-RPC requests.
+type rpcCaller interface {
+	CallContext(ctx context.Context, result any, method string, args ...any) error
+}
+
+// CallOverride makes multicalls like Call against the latest state modified by the given
+// overrides. This needs a caller created with Dial.
+func (caller *Caller) CallOverride(ctx context.Context, overrides map[common.Address]OverrideAccount, calls ...*Call) ([]*Call, error) {
+	return caller.callRaw(ctx, calls, "latest", overrides)
+}
+
+// CallAtHash makes multicalls like Call at the block with the given hash, which is not
+// affected by the reorgs replacing the block number, unlike CallAt. The block is read even
+// when it is no longer canonical, as long as the node still has its state. This needs a
+// caller created with Dial.
+func (caller *Caller) CallAtHash(blockHash common.Hash, calls ...*Call) ([]*Call, error) {
+	ctx, _ := caller.callContext(nil)
+	return caller.callRaw(ctx, calls, rpc.BlockNumberOrHashWithHash(blockHash, false))
+}
+
+// callRaw makes the multicalls with a raw eth_call request, using the given request
+// arguments after the call object.
+func (caller *Caller) callRaw(ctx context.Context, calls []*Call, args ...any) ([]*Call, error) {
+	if caller.rpc == nil {
+		return calls, errors.New("caller has no rpc client")
+	}
+
+	packedCalls, multiCalls, multiErr, err := caller.pack(calls)
+	if err != nil {
+		return calls, err
+	}
+
+	multicall, err := caller.multicallContract()
+	if err != nil {
+		return calls, err
+	}
+	data, err := multicall.ABI.Pack("aggregate3", multiCalls)
+	if err != nil {
+		return calls, fmt.Errorf("failed to pack multicall: %v", err)
+	}
+
+	callArg := map[string]any{
+		"to":   caller.address,
+		"data": hexutil.Bytes(data),
+	}
+	var result hexutil.Bytes
+	if err := caller.rpc.CallContext(ctx, &result, "eth_call", append([]any{callArg}, args...)...); err != nil {
+		return calls, multicallError(err)
+	}
+
+	out, err := multicall.ABI.Unpack("aggregate3", result)
+	if err != nil {
+		return calls, fmt.Errorf("failed to unpack multicall results: %v", err)
+	}
+	results := *abi.ConvertType(out[0], new([]contract_multicall.Multicall3Result)).(*[]contract_multicall.Multicall3Result)
+
+	if err := caller.unpackResults(packedCalls, results); err != nil {
+		return calls, err
+	}
+	collectCallErrors(&multiErr, calls)
+	return calls, multiErr.errOrNil()
+}
@@ -0,0 +1,123 @@
+package multicall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcStub handles eth_call requests to aggregate3 by returning the inputs of each call
+// as its outputs.
+type rpcStub struct {
+	t      *testing.T
+	method string
+	args   []any
+	err    error
+}
+
+func (rs *rpcStub) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	r := require.New(rs.t)
+
+	rs.method = method
+	rs.args = args
+	if rs.err != nil {
+		return rs.err
+	}
+
+	multicallABI, err := contract_multicall.MulticallMetaData.GetAbi()
+	r.NoError(err)
+
+	data := args[0].(map[string]any)["data"].(hexutil.Bytes)
+	in, err := multicallABI.Methods["aggregate3"].Inputs.Unpack(data[4:])
+	r.NoError(err)
+	calls := *abi.ConvertType(in[0], new([]contract_multicall.Multicall3Call3)).(*[]contract_multicall.Multicall3Call3)
+
+	var results []contract_multicall.Multicall3Result
+	for _, call := range calls {
+		results = append(results, contract_multicall.Multicall3Result{
+			Success:    true,
+			ReturnData: call.CallData[4:],
+		})
+	}
+	out, err := multicallABI.Methods["aggregate3"].Outputs.Pack(results)
+	r.NoError(err)
+	*result.(*hexutil.Bytes) = out
+	return nil
+}
+
+func TestCaller_CallOverride(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	stub := &rpcStub{t: t}
+	caller := &Caller{rpc: stub}
+
+	overrides := map[common.Address]OverrideAccount{
+		common.HexToAddress(testAddr2): {Balance: big.NewInt(1)},
+	}
+	calls, err := caller.CallOverride(context.Background(), overrides,
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", false),
+	)
+	r.NoError(err)
+	r.Equal("eth_call", stub.method)
+	r.Equal("latest", stub.args[1])
+	r.Equal(overrides, stub.args[2])
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.False(calls[1].Outputs.(*boolOutput).Val1)
+
+	stub.err = errors.New("method not found")
+	_, err = caller.CallOverride(context.Background(), overrides, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorContains(err, "method not found")
+
+	_, err = (&Caller{}).CallOverride(context.Background(), overrides)
+	r.ErrorContains(err, "no rpc client")
+}
+
+func TestCaller_CallAtHash(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	stub := &rpcStub{t: t}
+	caller := &Caller{rpc: stub}
+
+	calls, err := caller.CallAtHash(testBlockHash,
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+	)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 1)
+	r.Equal("eth_call", stub.method)
+	r.Len(stub.args, 2)
+	blockArg, err := json.Marshal(stub.args[1])
+	r.NoError(err)
+	r.JSONEq(`{"blockHash": "`+testBlockHash.Hex()+`"}`, string(blockArg))
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+
+	_, err = (&Caller{}).CallAtHash(testBlockHash)
+	r.ErrorContains(err, "no rpc client")
+}
+
+func TestOverrideAccount_MarshalJSON(t *testing.T) {
+	r := require.New(t)
+
+	b, err := json.Marshal(OverrideAccount{
+		Balance: big.NewInt(16),
+		Code:    []byte{},
+	})
+	r.NoError(err)
+	r.JSONEq(`{"balance":"0x10","code":"0x"}`, string(b))
+}
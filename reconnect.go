@@ -0,0 +1,139 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DialReconnecting is like Dial but redials the endpoint when a request fails with a
+// connection error and retries the request once. The requests which fail on the node,
+// like reverted calls, are not retried. The caller cannot send transactions.
+func DialReconnecting(ctx context.Context, rawUrl string, opts ...Option) (*Caller, error) {
+	rpcClient, err := rpc.DialContext(ctx, rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	client := &reconnectingClient{rawUrl: rawUrl, rpc: rpcClient, dial: rpc.DialContext}
+	caller, err := NewContext(ctx, client, opts...)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	caller.rpc = client
+	caller.closer = closeOnce(client.Close)
+	return caller, nil
+}
+
+// reconnectingClient is a backend client which redials the endpoint on connection errors.
+type reconnectingClient struct {
+	rawUrl string
+	dial   func(ctx context.Context, rawUrl string) (*rpc.Client, error)
+
+	mu     sync.Mutex
+	rpc    *rpc.Client
+	closed bool
+}
+
+func (rc *reconnectingClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = rc.do(ctx, func(rpcClient *rpc.Client) (err error) {
+		code, err = ethclient.NewClient(rpcClient).CodeAt(ctx, contract, blockNumber)
+		return
+	})
+	return
+}
+
+func (rc *reconnectingClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (returnData []byte, err error) {
+	err = rc.do(ctx, func(rpcClient *rpc.Client) (err error) {
+		returnData, err = ethclient.NewClient(rpcClient).CallContract(ctx, call, blockNumber)
+		return
+	})
+	return
+}
+
+func (rc *reconnectingClient) ChainID(ctx context.Context) (chainID *big.Int, err error) {
+	err = rc.do(ctx, func(rpcClient *rpc.Client) (err error) {
+		chainID, err = ethclient.NewClient(rpcClient).ChainID(ctx)
+		return
+	})
+	return
+}
+
+func (rc *reconnectingClient) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	return rc.do(ctx, func(rpcClient *rpc.Client) error {
+		return rpcClient.CallContext(ctx, result, method, args...)
+	})
+}
+
+// do makes the request and retries it once with a new connection on a connection error.
+func (rc *reconnectingClient) do(ctx context.Context, request func(rpcClient *rpc.Client) error) error {
+	rpcClient := rc.current()
+	err := request(rpcClient)
+	if err == nil || !isConnectionError(err) {
+		return err
+	}
+	if err := rc.redial(ctx, rpcClient); err != nil {
+		return fmt.Errorf("failed to reconnect: %v", err)
+	}
+	return request(rc.current())
+}
+
+// Close closes the current connection and stops redialing.
+func (rc *reconnectingClient) Close() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.closed = true
+	rc.rpc.Close()
+}
+
+func (rc *reconnectingClient) current() *rpc.Client {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.rpc
+}
+
+// redial replaces the failed client unless it was already replaced by another request.
+func (rc *reconnectingClient) redial(ctx context.Context, failed *rpc.Client) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.closed {
+		return rpc.ErrClientQuit
+	}
+	if rc.rpc != failed {
+		return nil
+	}
+	rpcClient, err := rc.dial(ctx, rc.rawUrl)
+	if err != nil {
+		return err
+	}
+	failed.Close()
+	rc.rpc = rpcClient
+	return nil
+}
+
+// isConnectionError tells if the error is about the connection to the node rather than
+// an error returned by the node.
+func isConnectionError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) || isRevert(err) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, rpc.ErrClientQuit) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
@@ -0,0 +1,86 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type ethService struct{}
+
+func (ethService) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(testChainID))
+}
+
+func (ethService) GetCode(addr common.Address, block string) (hexutil.Bytes, error) {
+	return nil, errors.New("execution reverted")
+}
+
+func TestReconnectingClient(t *testing.T) {
+	r := require.New(t)
+
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", ethService{}))
+	defer server.Stop()
+
+	var dials int
+	client := &reconnectingClient{
+		rpc: rpc.DialInProc(server),
+		dial: func(ctx context.Context, rawUrl string) (*rpc.Client, error) {
+			dials++
+			return rpc.DialInProc(server), nil
+		},
+	}
+
+	chainID, err := client.ChainID(context.Background())
+	r.NoError(err)
+	r.Equal(big.NewInt(testChainID), chainID)
+	r.Zero(dials)
+
+	// the connection is lost
+	client.current().Close()
+	chainID, err = client.ChainID(context.Background())
+	r.NoError(err)
+	r.Equal(big.NewInt(testChainID), chainID)
+	r.Equal(1, dials)
+
+	// errors from the node are not retried
+	_, err = client.CodeAt(context.Background(), common.HexToAddress(testAddr1), nil)
+	r.ErrorContains(err, "execution reverted")
+	r.Equal(1, dials)
+
+	// failing to reconnect
+	client.current().Close()
+	client.dial = func(ctx context.Context, rawUrl string) (*rpc.Client, error) {
+		return nil, errors.New("connection refused")
+	}
+	_, err = client.ChainID(context.Background())
+	r.EqualError(err, "failed to reconnect: connection refused")
+
+	// closing stops redialing
+	client.dial = func(ctx context.Context, rawUrl string) (*rpc.Client, error) {
+		dials++
+		return rpc.DialInProc(server), nil
+	}
+	client.Close()
+	_, err = client.ChainID(context.Background())
+	r.ErrorContains(err, rpc.ErrClientQuit.Error())
+	r.Equal(1, dials)
+}
+
+func TestIsConnectionError(t *testing.T) {
+	r := require.New(t)
+
+	r.True(isConnectionError(rpc.ErrClientQuit))
+	r.True(isConnectionError(fmt.Errorf("post failed: %w", &net.OpError{Op: "dial", Err: errors.New("refused")})))
+	r.False(isConnectionError(errors.New("execution reverted")))
+	r.False(isConnectionError(errors.New("out of gas")))
+}
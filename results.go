@@ -0,0 +1,173 @@
+package multicall
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GroupByContract groups the calls by their contract addresses. The order of the calls
+// is kept in each group.
+func GroupByContract(calls []*Call) map[common.Address][]*Call {
+	groups := make(map[common.Address][]*Call)
+	for _, call := range calls {
+		if call == nil || call.Contract == nil {
+			continue
+		}
+		groups[call.Contract.Address] = append(groups[call.Contract.Address], call)
+	}
+	return groups
+}
+
+// FailedCalls returns the indexes of the calls which failed on chain.
+func FailedCalls(calls []*Call) []int {
+	var failed []int
+	for i, call := range calls {
+		if call.Failed {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+// SuccessCount returns the number of the calls which succeeded on chain.
+func SuccessCount(calls []*Call) int {
+	return len(calls) - len(FailedCalls(calls))
+}
+
+// SortByIndex sorts the calls in place by their sequence indexes, see Call.WithIndex. The
+// calls with the same index keep their order.
+func SortByIndex(calls []*Call) {
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].Index < calls[j].Index
+	})
+}
+
+// CallDiff is the change of a call's result between two runs of the same calls, e.g. at
+// two blocks. Before and After are the raw return data of the call.
+type CallDiff struct {
+	Index  int
+	Before []byte
+	After  []byte
+}
+
+// DiffResults pairs the already made calls by their indexes and returns the calls whose
+// return data or failure changed between before and after. An error is returned when
+// the slices differ in length or the calls at an index have different targets.
+func DiffResults(before, after []*Call) ([]CallDiff, error) {
+	if len(before) != len(after) {
+		return nil, fmt.Errorf("cannot diff %d calls with %d calls", len(before), len(after))
+	}
+	var diffs []CallDiff
+	for i := range before {
+		if callTarget(before[i]) != callTarget(after[i]) {
+			return nil, fmt.Errorf("calls at index [%d] have different targets %s and %s",
+				i, callTarget(before[i]).Hex(), callTarget(after[i]).Hex())
+		}
+		if before[i].Failed == after[i].Failed && bytes.Equal(before[i].RawReturn, after[i].RawReturn) {
+			continue
+		}
+		diffs = append(diffs, CallDiff{
+			Index:  i,
+			Before: before[i].RawReturn,
+			After:  after[i].RawReturn,
+		})
+	}
+	return diffs, nil
+}
+
+// callTarget returns the address of the call's contract.
+func callTarget(call *Call) common.Address {
+	if call.Contract == nil {
+		return common.Address{}
+	}
+	return call.Contract.Address
+}
+
+// Result is the outcome of a call made with Caller.CallResults. Err is the error of the
+// call, which failed to pack, failed on chain or failed to unpack, and Success tells that
+// there is no error.
+type Result struct {
+	Index      int
+	Success    bool
+	ReturnData []byte
+	Outputs    any
+	Err        error
+}
+
+// CallResults makes multicalls like Call without updating the given calls, so the same
+// calls can be made again, e.g. at other blocks. The outputs of each result are of the
+// same type as the outputs of the call. The *MultiError of the failed calls is returned
+// along with the results, and only the error is returned for other errors.
+func (caller *Caller) CallResults(opts *bind.CallOpts, calls ...*Call) ([]Result, error) {
+	clones := cloneCalls(calls)
+	for _, call := range clones {
+		call.Failed = false
+		call.RawReturn = nil
+		call.UnpackErr = nil
+	}
+	_, err := caller.Call(opts, clones...)
+	multiErr := new(MultiError)
+	if err != nil && !errors.As(err, &multiErr) {
+		return nil, err
+	}
+
+	results := make([]Result, len(clones))
+	for i, call := range clones {
+		results[i] = Result{
+			Index:      i,
+			Success:    true,
+			ReturnData: call.RawReturn,
+			Outputs:    call.Outputs,
+		}
+	}
+	for _, callErr := range multiErr.Errors {
+		results[callErr.Index].Success = false
+		results[callErr.Index].Err = callErr.Err
+	}
+	return results, err
+}
+
+// CallOutcome is the outcome of a call made with Caller.CallOutcomes. Outputs are the
+// decoded outputs of the call in the ABI order. Err is the error of the call, which failed
+// to pack, failed on chain or failed to unpack, and Success tells that there is no error.
+type CallOutcome struct {
+	Success   bool
+	Outputs   []any
+	RawReturn []byte
+	Err       error
+}
+
+// CallOutcomes makes multicalls like CallResults and returns the outcome of each call. The
+// failures of the calls are only in the outcomes, so the returned error is only for the
+// failure of the whole multicall. The outcomes are made the same way with a strict caller.
+func (caller *Caller) CallOutcomes(opts *bind.CallOpts, calls ...*Call) ([]CallOutcome, error) {
+	results, err := caller.lenient().CallResults(opts, calls...)
+	if err != nil && !errors.As(err, new(*MultiError)) {
+		return nil, err
+	}
+
+	outcomes := make([]CallOutcome, len(results))
+	for i, result := range results {
+		outcomes[i] = CallOutcome{
+			Success:   result.Success,
+			RawReturn: result.ReturnData,
+			Err:       result.Err,
+		}
+		if !result.Success || calls[i].raw {
+			continue
+		}
+		outputs, err := calls[i].Contract.ABI.Unpack(calls[i].Method, result.ReturnData)
+		if err != nil {
+			outcomes[i].Success = false
+			outcomes[i].Err = fmt.Errorf("failed to unpack '%s' outputs: %v", calls[i].Method, err)
+			continue
+		}
+		outcomes[i].Outputs = outputs
+	}
+	return outcomes, nil
+}
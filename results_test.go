@@ -0,0 +1,221 @@
+package multicall
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByContract(t *testing.T) {
+	r := require.New(t)
+
+	testContract1, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	testContract2, err := NewContract(oneValueABI, testAddr2)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract1.NewCall(nil, "testFunc", true).Name("1a"),
+		testContract2.NewCall(nil, "testFunc", true).Name("2a"),
+		testContract1.NewCall(nil, "testFunc", true).Name("1b"),
+		testContract2.NewCall(nil, "testFunc", true).Name("2b"),
+		testContract1.NewCall(nil, "testFunc", true).Name("1c"),
+	}
+
+	groups := GroupByContract(calls)
+	r.Len(groups, 2)
+	r.Equal([]*Call{calls[0], calls[2], calls[4]}, groups[common.HexToAddress(testAddr1)])
+	r.Equal([]*Call{calls[1], calls[3]}, groups[common.HexToAddress(testAddr2)])
+	r.Empty(GroupByContract(nil))
+}
+
+func TestFailedCalls(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 4; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure())
+	}
+	stub := echoStub()
+	stub.failures = map[int]bool{1: true, 3: true}
+	calls, err = (&Caller{contract: stub}).TryCall(nil, false, calls...)
+	r.NoError(err)
+
+	r.Equal([]int{1, 3}, FailedCalls(calls))
+	r.Equal(2, SuccessCount(calls))
+	r.Empty(FailedCalls(nil))
+	r.Zero(SuccessCount(nil))
+}
+
+func TestSortByIndex(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract.NewCall(nil, "testFunc", true).Name("c").WithIndex(2),
+		testContract.NewCall(nil, "testFunc", true).Name("a").WithIndex(0),
+		testContract.NewCall(nil, "testFunc", true).Name("b1").WithIndex(1),
+		testContract.NewCall(nil, "testFunc", true).Name("b2").WithIndex(1),
+	}
+
+	SortByIndex(calls)
+	var names []string
+	for _, call := range calls {
+		names = append(names, call.CallName)
+	}
+	r.Equal([]string{"a", "b1", "b2", "c"}, names)
+}
+
+func TestDiffResults(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	otherContract, err := NewContract(oneValueABI, testAddr2)
+	r.NoError(err)
+
+	newCalls := func(returns ...[]byte) []*Call {
+		var calls []*Call
+		for _, data := range returns {
+			call := testContract.NewCall(nil, "testFunc", true)
+			call.RawReturn = data
+			calls = append(calls, call)
+		}
+		return calls
+	}
+	before := newCalls([]byte{1}, []byte{2}, nil)
+	after := newCalls([]byte{1}, []byte{3}, nil)
+	after[2].Failed = true
+
+	diffs, err := DiffResults(before, after)
+	r.NoError(err)
+	r.Equal([]CallDiff{
+		{Index: 1, Before: []byte{2}, After: []byte{3}},
+		{Index: 2},
+	}, diffs)
+
+	diffs, err = DiffResults(before, before)
+	r.NoError(err)
+	r.Empty(diffs)
+
+	_, err = DiffResults(before, after[:2])
+	r.ErrorContains(err, "cannot diff 3 calls with 2 calls")
+
+	after[0].Contract = otherContract
+	_, err = DiffResults(before, after)
+	r.ErrorContains(err, "calls at index [0] have different targets")
+}
+
+func TestCaller_CallResults(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+		testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure(),
+	}
+
+	stub := echoStub()
+	stub.failures = map[int]bool{1: true} // index in the packed batch
+	caller := &Caller{contract: stub}
+
+	for i := 0; i < 2; i++ {
+		results, err := caller.CallResults(nil, calls...)
+		var multiErr *MultiError
+		r.ErrorAs(err, &multiErr)
+		r.Len(results, 3)
+
+		r.Equal(0, results[0].Index)
+		r.True(results[0].Success)
+		r.NoError(results[0].Err)
+		r.True(results[0].Outputs.(*boolOutput).Val1)
+		r.NotEmpty(results[0].ReturnData)
+
+		r.False(results[1].Success)
+		r.ErrorContains(results[1].Err, "pack")
+
+		r.Equal(2, results[2].Index)
+		r.False(results[2].Success)
+		r.ErrorIs(results[2].Err, ErrCallFailed)
+	}
+
+	// the calls are not updated
+	for _, call := range calls {
+		r.False(call.Failed)
+		r.Nil(call.RawReturn)
+		r.False(call.Outputs.(*boolOutput).Val1)
+	}
+
+	stub.callErr = func([]contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	results, err := caller.CallResults(nil, calls...)
+	r.ErrorContains(err, "rpc down")
+	r.Nil(results)
+}
+
+func TestCaller_CallOutcomes(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	calls := []*Call{
+		testContract.NewCall(nil, "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+		testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure(),
+		NewRawCall(common.HexToAddress(testAddr2), []byte{0x01, 0x02, 0x03, 0x04, 0x05}, false),
+	}
+
+	stub := echoStub()
+	stub.failures = map[int]bool{1: true} // index in the packed batch
+	caller := &Caller{contract: stub}
+
+	outcomes, err := caller.CallOutcomes(nil, calls...)
+	r.NoError(err)
+	r.Len(outcomes, 4)
+
+	r.True(outcomes[0].Success)
+	r.Equal([]any{true}, outcomes[0].Outputs)
+	r.NotEmpty(outcomes[0].RawReturn)
+	r.NoError(outcomes[0].Err)
+
+	r.False(outcomes[1].Success)
+	r.ErrorContains(outcomes[1].Err, "pack")
+
+	r.False(outcomes[2].Success)
+	r.ErrorIs(outcomes[2].Err, ErrCallFailed)
+	r.Nil(outcomes[2].Outputs)
+
+	r.True(outcomes[3].Success)
+	r.Nil(outcomes[3].Outputs)
+	r.Equal([]byte{0x05}, outcomes[3].RawReturn)
+
+	// the failures of a strict caller are also in the outcomes
+	outcomes, err = caller.Strict().CallOutcomes(nil, calls...)
+	r.NoError(err)
+	r.Len(outcomes, 4)
+	r.True(outcomes[0].Success)
+	r.ErrorContains(outcomes[1].Err, "pack")
+	r.ErrorIs(outcomes[2].Err, ErrCallFailed)
+	r.True(outcomes[3].Success)
+
+	stub.failures = nil
+	stub.callErr = func([]contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	outcomes, err = caller.CallOutcomes(nil, calls[0])
+	r.ErrorContains(err, "rpc down")
+	r.Nil(outcomes)
+}
@@ -0,0 +1,65 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// CallChunkedRetry is like CallChunked but retries a chunk up to maxRetries times when
+// sending the multicall fails, e.g. with a connection or a rate limit error. The backoff
+// starts from the cooldown and doubles after each attempt. Each attempt makes the chunk like
+// CallChunked, and the reverts of the multicall, the pack and unpack errors and the
+// failures of the calls are not retried since they would fail again.
+func (caller *Caller) CallChunkedRetry(opts *bind.CallOpts, chunkSize int, cooldown time.Duration, maxRetries int, calls ...*Call) ([]*Call, error) {
+	if err := checkChunkSize(chunkSize); err != nil {
+		return calls, err
+	}
+	ctx, _ := caller.callContext(opts)
+	return caller.callChunks(ctx, opts, calls, ChunkSlice(chunkSize, calls), cooldown, func(chunkOpts *bind.CallOpts, chunk []*Call) ([]*Call, error) {
+		return caller.callRetry(chunkOpts, cooldown, maxRetries, chunk...)
+	})
+}
+
+func (caller *Caller) callRetry(opts *bind.CallOpts, backoff time.Duration, maxRetries int, calls ...*Call) ([]*Call, error) {
+	ctx, _ := caller.callContext(opts)
+	for attempt := 0; ; attempt++ {
+		_, err := caller.callSplitting(opts, calls)
+		var sendErr *sendError
+		if !errors.As(err, &sendErr) || ctx.Err() != nil {
+			return calls, err
+		}
+		if attempt >= maxRetries {
+			return calls, multicallError(fmt.Errorf("failed after %d attempts: %w", attempt+1, sendErr.err))
+		}
+		// the retried attempts are observed here and the last attempt with the chunk
+		caller.observeRateLimit(err)
+		if err := sleepContext(ctx, backoff<<attempt); err != nil {
+			return calls, err
+		}
+	}
+}
+
+// RetryFailed retries the already made calls which have failed, up to maxRetries times.
+// Only the failed calls are sent again using TryAggregate and their results are updated
+// in place, so the given calls are returned in the same order. The retries stop early
+// when no call has failed.
+func (caller *Caller) RetryFailed(opts *bind.CallOpts, maxRetries int, calls ...*Call) ([]*Call, error) {
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		var failed []*Call
+		for _, call := range calls {
+			if call.Failed {
+				failed = append(failed, call)
+			}
+		}
+		if len(failed) == 0 {
+			break
+		}
+		if _, err := caller.TryCall(opts, false, failed...); err != nil {
+			return calls, err
+		}
+	}
+	return calls, nil
+}
@@ -0,0 +1,222 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_CallChunkedRetry(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call1 := testContract.NewCall(new(boolOutput), "testFunc", true)
+	call2 := testContract.NewCall(new(boolOutput), "testFunc", false)
+
+	var attempts int
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		attempts++
+		if attempts%3 != 0 {
+			return errors.New("429 too many requests")
+		}
+		return nil
+	}
+	caller := &Caller{contract: stub}
+
+	calls, err := caller.CallChunkedRetry(nil, 1, time.Millisecond, 2, call1, call2)
+	r.NoError(err)
+	r.Equal(6, attempts)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.False(calls[1].Outputs.(*boolOutput).Val1)
+}
+
+func TestCaller_CallChunkedRetryExhausted(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var attempts int
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		attempts++
+		return errors.New("503 service unavailable")
+	}
+	caller := &Caller{contract: stub}
+
+	_, err = caller.CallChunkedRetry(nil, 1, time.Millisecond, 2, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.Error(err)
+	r.ErrorContains(err, "after 3 attempts")
+	r.Equal(3, attempts)
+}
+
+func TestCaller_CallChunkedRetryAdaptiveCooldown(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("429 too many requests")
+	}
+	caller := &Caller{contract: stub}
+	WithAdaptiveCooldown(time.Millisecond, 2, time.Second, nil)(caller)
+
+	// the rate limit is observed once for each attempt
+	_, err = caller.CallChunkedRetry(nil, 1, time.Millisecond, 2, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorContains(err, "after 3 attempts")
+	r.Equal(4*time.Millisecond, caller.backpressure.current())
+}
+
+func TestCaller_CallChunkedRetryUnpackError(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var attempts int
+	caller := &Caller{
+		contract: &multicallStub{
+			returnData: func(calls []contract_multicall.Multicall3Call3) [][]byte {
+				attempts++
+				return [][]byte{{'a'}}
+			},
+		},
+	}
+
+	_, err = caller.Strict().CallChunkedRetry(nil, 1, time.Millisecond, 2, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.Error(err)
+	r.ErrorContains(err, "unpack")
+	r.Equal(1, attempts)
+}
+
+func TestCaller_CallChunkedRetryContextDeadline(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var (
+		attempts int
+		callCtx  context.Context
+	)
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		callCtx = opts.Context
+	}
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		attempts++
+		<-callCtx.Done()
+		return callCtx.Err()
+	}
+	caller := &Caller{contract: stub}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = caller.CallChunkedRetry(&bind.CallOpts{Context: ctx}, 1, time.Hour, 5, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorIs(err, context.DeadlineExceeded)
+	r.Less(time.Since(start), time.Minute)
+	r.Equal(1, attempts)
+}
+
+func TestCaller_CallChunkedRetryRevert(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var attempts int
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		attempts++
+		return &dataErrorStub{data: "0x"}
+	}
+	caller := &Caller{contract: stub}
+
+	_, err = caller.CallChunkedRetry(nil, 1, time.Millisecond, 2, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.ErrorIs(err, ErrBatchReverted)
+	r.Equal(1, attempts)
+}
+
+func TestCaller_CallChunkedRetryMatchesCallChunked(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	newCalls := func() []*Call {
+		return []*Call{
+			testContract.NewCall(new(boolOutput), "testFunc", true),
+			testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+			testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure(),
+		}
+	}
+	stub := echoStub()
+	stub.failures = map[int]bool{1: true}
+	caller := &Caller{contract: stub}
+
+	chunked, chunkedErr := caller.CallChunked(nil, 3, 0, newCalls()...)
+	retried, retryErr := caller.CallChunkedRetry(nil, 3, time.Millisecond, 2, newCalls()...)
+	r.Equal(chunkedErr, retryErr)
+	var multiErr *MultiError
+	r.ErrorAs(retryErr, &multiErr)
+	r.Len(multiErr.Errors, 2)
+	for i := range chunked {
+		r.Equal(chunked[i].Failed, retried[i].Failed)
+		r.Equal(chunked[i].Outputs, retried[i].Outputs)
+	}
+}
+
+func TestCaller_RetryFailed(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", i != 1))
+	}
+
+	var sent []int
+	stub := echoStub()
+	stub.failures = map[int]bool{0: true, 2: true}
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		sent = append(sent, len(calls))
+		return nil
+	}
+	caller := &Caller{contract: stub}
+
+	calls, err = caller.TryCall(nil, false, calls...)
+	r.NoError(err)
+	r.True(calls[0].Failed)
+	r.False(calls[1].Failed)
+	r.True(calls[2].Failed)
+
+	// the first call in each retry keeps failing
+	stub.failures = map[int]bool{0: true}
+	calls, err = caller.RetryFailed(nil, 3, calls...)
+	r.NoError(err)
+	r.Equal([]int{3, 2, 1, 1}, sent)
+	r.True(calls[0].Failed)
+	r.False(calls[1].Failed)
+	r.False(calls[2].Failed)
+	r.True(calls[2].Outputs.(*boolOutput).Val1)
+
+	// nothing to retry
+	sent = nil
+	stub.failures = nil
+	_, err = caller.RetryFailed(nil, 3, calls...)
+	r.NoError(err)
+	r.Equal([]int{1}, sent)
+}
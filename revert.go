@@ -0,0 +1,62 @@
+package multicall
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// RevertError is a custom Solidity error decoded from the return data of a failed call.
+type RevertError struct {
+	Name string
+	Args []interface{}
+}
+
+// Error implements the error interface.
+func (err *RevertError) Error() string {
+	args := make([]string, len(err.Args))
+	for i, arg := range err.Args {
+		args[i] = fmt.Sprint(arg)
+	}
+	return fmt.Sprintf("%s(%s)", err.Name, strings.Join(args, ", "))
+}
+
+// DecodeRevert matches the selector of the revert data against the custom errors of the
+// given ABIs and decodes the first matching error. It returns false if no error matches.
+func DecodeRevert(data []byte, abis ...*abi.ABI) (*RevertError, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+	for _, parsed := range abis {
+		if parsed == nil {
+			continue
+		}
+		for _, abiErr := range parsed.Errors {
+			if !bytes.Equal(abiErr.ID[:4], data[:4]) {
+				continue
+			}
+			args, err := abiErr.Inputs.Unpack(data[4:])
+			if err != nil {
+				continue
+			}
+			return &RevertError{Name: abiErr.Name, Args: args}, true
+		}
+	}
+	return nil, false
+}
+
+// DecodeRevert decodes the return data of a failed call as a custom error of the call's
+// contract ABI or of the ABIs registered with WithErrorABIs. It returns false if the call
+// did not fail or no error matches.
+func (caller *Caller) DecodeRevert(call *Call) (*RevertError, bool) {
+	if !call.Failed {
+		return nil, false
+	}
+	abis := caller.errorABIs
+	if call.Contract != nil {
+		abis = append([]*abi.ABI{call.Contract.ABI}, abis...)
+	}
+	return DecodeRevert(call.RawReturn, abis...)
+}
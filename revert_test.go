@@ -0,0 +1,56 @@
+package multicall
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const customErrorABI = `[
+	{
+		"inputs": [{"internalType": "uint256", "name": "available", "type": "uint256"}],
+		"name": "InsufficientBalance",
+		"type": "error"
+	}
+]`
+
+func TestDecodeRevert(t *testing.T) {
+	r := require.New(t)
+
+	errorABI, err := ParseABI(customErrorABI)
+	r.NoError(err)
+	abiErr := errorABI.Errors["InsufficientBalance"]
+	args, err := abiErr.Inputs.Pack(big.NewInt(42))
+	r.NoError(err)
+	data := append(append([]byte{}, abiErr.ID[:4]...), args...)
+
+	revertErr, ok := DecodeRevert(data, errorABI)
+	r.True(ok)
+	r.Equal("InsufficientBalance", revertErr.Name)
+	r.Equal([]interface{}{big.NewInt(42)}, revertErr.Args)
+	r.EqualError(revertErr, "InsufficientBalance(42)")
+
+	_, ok = DecodeRevert(data)
+	r.False(ok)
+	_, ok = DecodeRevert([]byte{0x01, 0x02, 0x03, 0x04}, errorABI)
+	r.False(ok)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	call := testContract.NewCall(new(boolOutput), "testFunc", true)
+	call.Failed = true
+	call.RawReturn = data
+
+	_, ok = NewWithContract(&multicallStub{}).DecodeRevert(call)
+	r.False(ok)
+
+	caller := NewWithContract(&multicallStub{}, WithErrorABIs(errorABI))
+	revertErr, ok = caller.DecodeRevert(call)
+	r.True(ok)
+	r.Equal("InsufficientBalance", revertErr.Name)
+
+	call.Failed = false
+	_, ok = caller.DecodeRevert(call)
+	r.False(ok)
+}
@@ -0,0 +1,31 @@
+package multicall
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SimulatedBackend is a simulated chain for tests, like backends.SimulatedBackend of
+// go-ethereum. The transactions sent with CallValue are mined with Commit.
+type SimulatedBackend interface {
+	bind.ContractBackend
+	Commit() common.Hash
+}
+
+// NewSimulated creates a new caller like NewBackend with a simulated backend and makes
+// sure that the multicall contract is deployed on it, since a simulated chain starts
+// empty. The multicall contract can be deployed by adding its code at the multicall
+// address to the genesis allocation of the backend. The simulated backends only make
+// calls at the latest block, so the calls should not set a block number.
+func NewSimulated(backend SimulatedBackend, opts ...Option) (*Caller, error) {
+	caller, err := NewBackend(backend, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := caller.verify(context.Background()); err != nil {
+		return nil, err
+	}
+	return caller, nil
+}
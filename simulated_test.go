@@ -0,0 +1,119 @@
+package multicall
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSimulated(t *testing.T) {
+	r := require.New(t)
+
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{}, 30000000)
+	defer backend.Close()
+
+	_, err := NewSimulated(backend)
+	r.ErrorContains(err, "no multicall contract deployed")
+}
+
+// multicall3CodeFile is the runtime code of the canonical Multicall3 contract in hex, as
+// returned by eth_getCode for DefaultAddress. It is written with make testdata.
+const multicall3CodeFile = "testdata/multicall3.hex"
+
+// TestSimulated runs the caller against the Multicall3 contract deployed on a simulated
+// backend with the genesis allocation.
+func TestSimulated(t *testing.T) {
+	r := require.New(t)
+
+	hexCode, err := os.ReadFile(multicall3CodeFile)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skipf("%s is missing, run make testdata", multicall3CodeFile)
+	}
+	r.NoError(err)
+	code, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(hexCode)), "0x"))
+	r.NoError(err)
+	r.NotEmpty(code)
+
+	key, err := crypto.GenerateKey()
+	r.NoError(err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	recipient := common.HexToAddress(testAddr2)
+	multicallAddr := common.HexToAddress(DefaultAddress)
+	balance := big.NewInt(1e18)
+
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		multicallAddr: {Code: code, Balance: big.NewInt(0)},
+		sender:        {Balance: balance},
+	}, 30000000)
+	defer backend.Close()
+
+	caller, err := NewSimulated(backend)
+	r.NoError(err)
+	multicall, err := caller.multicallContract()
+	r.NoError(err)
+
+	// aggregate3 with a call which is allowed to fail
+	calls, err := caller.Call(nil,
+		multicall.NewCall(new(ethBalanceOutput), "getEthBalance", sender),
+		NewRawCall(multicallAddr, []byte{0xde, 0xad, 0xbe, 0xef}, true),
+		multicall.NewCall(new(ethBalanceOutput), "getEthBalance", recipient),
+	)
+	r.ErrorIs(err, ErrCallFailed)
+	r.Equal(balance, calls[0].Outputs.(*ethBalanceOutput).Balance)
+	r.True(calls[1].Failed)
+	r.False(calls[2].Failed)
+	r.Zero(calls[2].Outputs.(*ethBalanceOutput).Balance.Sign())
+
+	// aggregate3 reverts when a call which is not allowed to fail fails
+	_, err = caller.Call(nil, NewRawCall(multicallAddr, []byte{0xde, 0xad, 0xbe, 0xef}, false))
+	r.ErrorIs(err, ErrBatchReverted)
+
+	// tryAggregate
+	calls, err = caller.TryCall(nil, false,
+		multicall.NewCall(new(ethBalanceOutput), "getEthBalance", sender),
+		NewRawCall(multicallAddr, []byte{0xde, 0xad, 0xbe, 0xef}, true),
+	)
+	r.NoError(err)
+	r.Equal(balance, calls[0].Outputs.(*ethBalanceOutput).Balance)
+	r.True(calls[1].Failed)
+
+	// aggregate3Value with a call which is allowed to fail
+	txOpts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337)) // simulated chain
+	r.NoError(err)
+	tx, err := caller.CallValue(txOpts,
+		NewRawCall(recipient, nil, false).WithValue(big.NewInt(1000)),
+		NewRawCall(multicallAddr, []byte{0xde, 0xad, 0xbe, 0xef}, true),
+		NewRawCall(recipient, nil, true).WithValue(big.NewInt(234)),
+	)
+	r.NoError(err)
+	backend.Commit()
+
+	receipt, err := backend.TransactionReceipt(context.Background(), tx.Hash())
+	r.NoError(err)
+	r.Equal(uint64(1), receipt.Status)
+	received, err := backend.BalanceAt(context.Background(), recipient, nil)
+	r.NoError(err)
+	r.Equal(big.NewInt(1234), received)
+	kept, err := backend.BalanceAt(context.Background(), multicallAddr, nil)
+	r.NoError(err)
+	r.Zero(kept.Sign())
+
+	// aggregate3Value reverts when a call which is not allowed to fail fails
+	txOpts.Value = nil
+	_, err = caller.CallValue(txOpts,
+		NewRawCall(recipient, nil, false).WithValue(big.NewInt(1000)),
+		NewRawCall(multicallAddr, []byte{0xde, 0xad, 0xbe, 0xef}, false),
+	)
+	r.ErrorContains(err, "multicall transaction failed")
+}
@@ -0,0 +1,72 @@
+package multicall
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// sizeErrorHints are the parts of the error messages which tell that a multicall was too
+// large for the node or the provider. They should not match the revert reasons of the
+// calls, like "transfer amount exceeds balance".
+var sizeErrorHints = []string{
+	"too large",
+	"limit exceeded",
+	"413",
+}
+
+// IsSizeError tells if the error looks like the multicall was too large for the node or
+// the provider. It is the default predicate of WithSplitOnSizeError.
+func IsSizeError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, hint := range sizeErrorHints {
+		if strings.Contains(msg, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// callSplitting makes the calls and, when the multicall fails with a size error, splits
+// the calls in halves recursively until the halves are not larger than the split floor.
+// The reverts of the multicall are never split, even with a custom predicate.
+func (caller *Caller) callSplitting(opts *bind.CallOpts, calls []*Call) ([]*Call, error) {
+	chunkOpts, cancel := caller.withChunkTimeout(opts)
+	_, err := caller.Call(chunkOpts, calls...)
+	cancel()
+	if !caller.shouldSplit(calls, err) {
+		return calls, err
+	}
+
+	caller.log().Debugf("multicall: splitting %d calls after error: %v", len(calls), err)
+	var multiErr MultiError
+	half := len(calls) / 2
+	for i, part := range [][]*Call{calls[:half], calls[half:]} {
+		_, err := caller.callSplitting(opts, part)
+		if partErr := (*MultiError)(nil); errors.As(err, &partErr) {
+			multiErr.merge(i*half, partErr)
+		} else if err != nil {
+			return calls, err
+		}
+	}
+	return calls, multiErr.errOrNil()
+}
+
+func (caller *Caller) shouldSplit(calls []*Call, err error) bool {
+	if err == nil || caller.splitFloor <= 0 || len(calls) <= caller.splitFloor || len(calls) < 2 {
+		return false
+	}
+	if multiErr := (*MultiError)(nil); errors.As(err, &multiErr) {
+		return false
+	}
+	// a reverted batch reverts the same way in halves
+	if errors.Is(err, ErrBatchReverted) {
+		return false
+	}
+	isSizeError := caller.isSizeError
+	if isSizeError == nil {
+		isSizeError = IsSizeError
+	}
+	return isSizeError(err)
+}
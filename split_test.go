@@ -0,0 +1,89 @@
+package multicall
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_SplitOnSizeError(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 10; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", i%2 == 0).AllowFailure())
+	}
+
+	// the provider rejects the multicalls with more than 3 calls
+	var sizes []int
+	stub := echoStub()
+	stub.failures = map[int]bool{0: true}
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		sizes = append(sizes, len(calls))
+		if len(calls) > 3 {
+			return errors.New("response too large")
+		}
+		return nil
+	}
+	caller := &Caller{contract: stub, splitFloor: 2}
+
+	calls, err = caller.CallChunked(nil, 10, 0, calls...)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	// the first call of each dispatched part fails
+	var indexes []int
+	for _, callErr := range multiErr.Errors {
+		indexes = append(indexes, callErr.Index)
+	}
+	r.Equal([]int{0, 2, 5, 7}, indexes)
+	r.Equal([]int{10, 5, 2, 3, 5, 2, 3}, sizes)
+	for i, call := range calls {
+		if !call.Failed {
+			r.Equal(i%2 == 0, call.Outputs.(*boolOutput).Val1)
+		}
+	}
+
+	// not a size error
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	_, err = caller.CallChunked(nil, 10, 0, calls...)
+	r.ErrorContains(err, "rpc down")
+
+	// custom predicate and the floor
+	sizes = nil
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		sizes = append(sizes, len(calls))
+		return errors.New("rpc down")
+	}
+	caller.splitFloor = 5
+	caller.isSizeError = func(err error) bool { return true }
+	_, err = caller.CallChunked(nil, 10, 0, calls...)
+	r.ErrorContains(err, "rpc down")
+	r.Equal([]int{10, 5}, sizes)
+
+	// a revert is not split even when it looks like a size error
+	sizes = nil
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		sizes = append(sizes, len(calls))
+		return &dataErrorStub{data: "0x"}
+	}
+	_, err = caller.CallChunked(nil, 10, 0, calls...)
+	r.ErrorIs(err, ErrBatchReverted)
+	r.Equal([]int{10}, sizes)
+}
+
+func TestIsSizeError(t *testing.T) {
+	r := require.New(t)
+
+	r.True(IsSizeError(errors.New("413 Request Entity Too Large")))
+	r.True(IsSizeError(errors.New("response size limit exceeded")))
+	r.False(IsSizeError(errors.New("execution reverted")))
+	r.False(IsSizeError(errors.New("execution reverted: ERC20: transfer amount exceeds balance")))
+	r.False(IsSizeError(errors.New("out of gas")))
+}
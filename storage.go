@@ -0,0 +1,110 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// storageWorkers is the number of the concurrent eth_getStorageAt requests of StorageAt.
+const storageWorkers = 8
+
+// StorageRequest is a storage slot of a contract to read with StorageAt. The latest block
+// is used when BlockNumber is nil.
+type StorageRequest struct {
+	Address     common.Address
+	Slot        common.Hash
+	BlockNumber *big.Int
+}
+
+// StorageAt reads the storage slots with eth_getStorageAt, since storage cannot be read
+// with a multicall. The requests are chunked with the default chunk size and cooldown of
+// the caller, and the requests of a chunk are made concurrently. The limiter and the
+// chunk hook of the caller are used for the chunks. The values are returned in the order
+// of the requests and the first error stops the rest. This needs a caller created with
+// Dial.
+func (caller *Caller) StorageAt(ctx context.Context, requests []StorageRequest) ([][]byte, error) {
+	if caller.rpc == nil {
+		return nil, errors.New("caller has no rpc client")
+	}
+
+	values := make([][]byte, len(requests))
+	chunkSize := caller.helperChunkSize()
+	for i, chunk := range ChunkSlice(chunkSize, requests) {
+		if d := caller.cooldownFor(caller.cooldown); i > 0 && d > 0 {
+			if err := sleepContext(ctx, d); err != nil {
+				return nil, err
+			}
+		}
+		if err := caller.wait(ctx); err != nil {
+			return nil, err
+		}
+		offset := i * chunkSize
+		start := time.Now()
+		err := caller.storageChunk(ctx, chunk, values[offset:offset+len(chunk)])
+		caller.chunkDone(i, len(chunk), start, err)
+		caller.observeRateLimit(err)
+		if err != nil {
+			return nil, newChunkError(i, offset, len(chunk), err)
+		}
+		caller.reportProgress(offset+len(chunk), len(requests))
+	}
+	return values, nil
+}
+
+// storageChunk reads the storage slots of a chunk concurrently into values.
+func (caller *Caller) storageChunk(ctx context.Context, requests []StorageRequest, values [][]byte) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	indexes := make(chan int)
+	for w := 0; w < storageWorkers && w < len(requests); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				request := requests[i]
+				block := "latest"
+				if request.BlockNumber != nil {
+					block = hexutil.EncodeBig(request.BlockNumber)
+				}
+				var value hexutil.Bytes
+				if err := caller.rpc.CallContext(ctx, &value, "eth_getStorageAt", request.Address, request.Slot, block); err != nil {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("failed to get storage of %s at slot %s: %v", request.Address.Hex(), request.Slot.Hex(), err)
+						cancel()
+					})
+					continue
+				}
+				values[i] = value
+			}
+		}()
+	}
+
+send:
+	for i := range requests {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
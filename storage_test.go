@@ -0,0 +1,71 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type storageService struct {
+	mu     sync.Mutex
+	blocks []string
+}
+
+// GetStorageAt returns the slot as the value and fails for the zero slot.
+func (service *storageService) GetStorageAt(addr common.Address, slot common.Hash, block string) (hexutil.Bytes, error) {
+	service.mu.Lock()
+	service.blocks = append(service.blocks, block)
+	service.mu.Unlock()
+	if slot == (common.Hash{}) {
+		return nil, errors.New("storage unavailable")
+	}
+	return slot.Bytes(), nil
+}
+
+func TestCaller_StorageAt(t *testing.T) {
+	r := require.New(t)
+
+	service := &storageService{}
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("eth", service))
+	defer server.Stop()
+
+	hook := &testChunkHook{}
+	caller := &Caller{rpc: rpc.DialInProc(server), chunkSize: 3, chunkHook: hook}
+
+	var requests []StorageRequest
+	for i := 1; i <= 20; i++ {
+		requests = append(requests, StorageRequest{
+			Address: common.HexToAddress(testAddr1),
+			Slot:    common.BigToHash(big.NewInt(int64(i))),
+		})
+	}
+	requests[0].BlockNumber = big.NewInt(testBlockNumber)
+
+	values, err := caller.StorageAt(context.Background(), requests)
+	r.NoError(err)
+	r.Len(values, 20)
+	for i, value := range values {
+		r.Equal(requests[i].Slot.Bytes(), value)
+	}
+	r.Len(hook.chunks, 7)
+	r.Len(service.blocks, 20)
+	r.Contains(service.blocks, hexutil.EncodeBig(big.NewInt(testBlockNumber)))
+
+	requests[4].Slot = common.Hash{}
+	_, err = caller.StorageAt(context.Background(), requests)
+	var chunkErr *ChunkError
+	r.ErrorAs(err, &chunkErr)
+	r.Equal(1, chunkErr.Chunk)
+	r.ErrorContains(err, "storage unavailable")
+
+	_, err = (&Caller{}).StorageAt(context.Background(), requests)
+	r.ErrorContains(err, "no rpc client")
+}
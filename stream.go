@@ -0,0 +1,72 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// ChunkResult is the result of a chunk streamed by CallStream.
+type ChunkResult struct {
+	// Index is the index of the chunk.
+	Index int
+	// Calls are the calls of the chunk.
+	Calls []*Call
+	// Err is the error of the chunk. The indexes of a *MultiError are the indexes of
+	// the calls in the whole batch.
+	Err error
+}
+
+// CallStream makes multiple multicalls by chunking given calls and sends the result of
+// each chunk to the returned channel as soon as the chunk is done. The chunks are made
+// one at a time and the next chunk is not made until the previous result is received.
+// The channel is closed when all chunks are done or the context is cancelled. When the
+// limiter fails, a last result with the error and no calls is sent before closing.
+func (caller *Caller) CallStream(ctx context.Context, opts *bind.CallOpts, chunkSize int, calls ...*Call) (<-chan ChunkResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := checkChunkSize(chunkSize); err != nil {
+		return nil, err
+	}
+
+	results := make(chan ChunkResult)
+	go func() {
+		defer close(results)
+		offset := 0
+		for i, chunk := range ChunkSlice(chunkSize, calls) {
+			if err := caller.wait(ctx); err != nil {
+				select {
+				case results <- ChunkResult{Index: i, Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			start := time.Now()
+			traceOpts, endTrace := caller.traceChunk(withContext(ctx, opts), i, chunk)
+			chunkOpts, cancel := caller.withChunkTimeout(traceOpts)
+			chunk, err := caller.Call(chunkOpts, chunk...)
+			cancel()
+			endTrace(err)
+			caller.chunkDone(i, len(chunk), start, err)
+			if chunkErr := (*MultiError)(nil); errors.As(err, &chunkErr) {
+				var multiErr MultiError
+				multiErr.merge(offset, chunkErr)
+				err = multiErr.errOrNil()
+			} else if err != nil {
+				err = newChunkError(i, offset, len(chunk), err)
+			}
+			offset += len(chunk)
+
+			select {
+			case results <- ChunkResult{Index: i, Calls: chunk, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results, nil
+}
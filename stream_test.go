@@ -0,0 +1,106 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_CallStream(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true).AllowFailure())
+	}
+
+	stub := echoStub()
+	stub.failures = map[int]bool{1: true}
+	caller := &Caller{contract: stub}
+
+	results, err := caller.CallStream(context.Background(), nil, 2, calls...)
+	r.NoError(err)
+
+	// the stub fails the second call of each chunk
+	var (
+		indexes    []int
+		count      int
+		errIndexes []int
+	)
+	for result := range results {
+		indexes = append(indexes, result.Index)
+		count += len(result.Calls)
+		var multiErr *MultiError
+		if errors.As(result.Err, &multiErr) {
+			for _, callErr := range multiErr.Errors {
+				errIndexes = append(errIndexes, callErr.Index)
+			}
+		}
+	}
+	r.Equal([]int{0, 1, 2}, indexes)
+	r.Equal(5, count)
+	r.Equal([]int{1, 3}, errIndexes)
+
+	// multicall error
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	results, err = caller.CallStream(context.Background(), nil, 2, calls...)
+	r.NoError(err)
+	result := <-results
+	r.ErrorContains(result.Err, "call chunk [0] failed")
+}
+
+func TestCaller_CallStreamCanceled(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	caller := &Caller{contract: echoStub()}
+	results, err := caller.CallStream(ctx, nil, 2, calls...)
+	r.NoError(err)
+
+	<-results
+	cancel()
+	for range results {
+	}
+
+	_, err = caller.CallStream(ctx, nil, 2, calls...)
+	r.ErrorIs(err, context.Canceled)
+}
+
+func TestCaller_CallStreamLimiterError(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 5; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	limiterErr := errors.New("rate limit exceeded")
+	caller := &Caller{contract: echoStub(), limiter: &testLimiter{err: limiterErr}}
+	results, err := caller.CallStream(context.Background(), nil, 2, calls...)
+	r.NoError(err)
+
+	var streamed []ChunkResult
+	for result := range results {
+		streamed = append(streamed, result)
+	}
+	r.Equal([]ChunkResult{{Index: 0, Err: limiterErr}}, streamed)
+}
@@ -0,0 +1,83 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// headSubscriber is a backend client which supports new head subscriptions, like
+// ethclient.Client with a websocket connection.
+type headSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+// Subscribe makes the calls with BlockAndAggregate at each new block and sends the results
+// to the returned channel. The calls are copied at each block, so the received calls are
+// not overwritten by the next block. The errors of the calls are sent to the error channel
+// and the subscription continues. When some of the calls fail to unpack, the *MultiError
+// is sent to the error channel and the calls are still sent. Both channels are closed when the context is cancelled
+// or the subscription fails. The backend client must support subscriptions.
+func (caller *Caller) Subscribe(ctx context.Context, calls []*Call) (<-chan []*Call, <-chan error, error) {
+	subscriber, ok := caller.client.(headSubscriber)
+	if !ok {
+		return nil, nil, errors.New("caller backend does not support subscriptions")
+	}
+	headers := make(chan *types.Header)
+	sub, err := subscriber.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to subscribe to new heads: %v", err)
+	}
+
+	results := make(chan []*Call)
+	errs := make(chan error)
+	go func() {
+		defer close(results)
+		defer close(errs)
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				if err != nil {
+					send(ctx, errs, fmt.Errorf("subscription failed: %v", err))
+				}
+				return
+			case header := <-headers:
+				blockCalls := cloneCalls(calls)
+				opts := &bind.CallOpts{Context: ctx, BlockNumber: header.Number}
+				if _, _, _, err := caller.BlockAndAggregate(opts, blockCalls...); err != nil {
+					send(ctx, errs, fmt.Errorf("block %s: %w", header.Number, err))
+					if !errors.As(err, new(*MultiError)) {
+						continue
+					}
+				}
+				send(ctx, results, blockCalls)
+			}
+		}
+	}()
+	return results, errs, nil
+}
+
+func cloneCalls(calls []*Call) []*Call {
+	clones := make([]*Call, len(calls))
+	for i, call := range calls {
+		clones[i] = call.clone()
+	}
+	return clones
+}
+
+// send sends the value to the channel unless the context is cancelled first.
+func send[T any](ctx context.Context, ch chan<- T, value T) bool {
+	select {
+	case ch <- value:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,102 @@
+package multicall
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+// subscriberStub sends the headers to the subscribers and then fails the subscription
+// with the given error, if there is any.
+type subscriberStub struct {
+	clientStub
+	headers []*types.Header
+	subErr  error
+}
+
+func (ss *subscriberStub) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for _, header := range ss.headers {
+			select {
+			case ch <- header:
+			case <-quit:
+				return nil
+			}
+		}
+		if ss.subErr != nil {
+			return ss.subErr
+		}
+		<-quit
+		return nil
+	}), nil
+}
+
+func TestCaller_Subscribe(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var blockNumbers []*big.Int
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		blockNumbers = append(blockNumbers, opts.BlockNumber)
+	}
+	client := &subscriberStub{
+		headers: []*types.Header{{Number: big.NewInt(1)}, {Number: big.NewInt(2)}},
+		subErr:  errors.New("connection lost"),
+	}
+	caller := &Caller{client: client, contract: stub}
+
+	calls := []*Call{testContract.NewCall(new(boolOutput), "testFunc", true)}
+	results, errs, err := caller.Subscribe(context.Background(), calls)
+	r.NoError(err)
+
+	first := <-results
+	second := <-results
+	r.True(first[0].Outputs.(*boolOutput).Val1)
+	r.True(second[0].Outputs.(*boolOutput).Val1)
+	r.NotSame(first[0], second[0])
+	r.NotSame(first[0].Outputs, second[0].Outputs)
+	r.False(calls[0].Outputs.(*boolOutput).Val1)
+	r.Equal([]*big.Int{big.NewInt(1), big.NewInt(2)}, blockNumbers)
+
+	r.EqualError(<-errs, "subscription failed: connection lost")
+	_, ok := <-results
+	r.False(ok)
+}
+
+func TestCaller_SubscribeErrors(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	caller := &Caller{client: &clientStub{}, contract: echoStub()}
+	_, _, err = caller.Subscribe(context.Background(), nil)
+	r.EqualError(err, "caller backend does not support subscriptions")
+
+	stub := echoStub()
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return errors.New("rpc down")
+	}
+	client := &subscriberStub{headers: []*types.Header{{Number: big.NewInt(1)}}}
+	caller = &Caller{client: client, contract: stub}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, errs, err := caller.Subscribe(ctx, []*Call{testContract.NewCall(new(boolOutput), "testFunc", true)})
+	r.NoError(err)
+	r.ErrorContains(<-errs, "block 1: multicall failed: rpc down")
+
+	cancel()
+	_, ok := <-results
+	r.False(ok)
+}
@@ -0,0 +1,23 @@
+package multicall
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// withChunkTimeout returns opts with a context which times out after the chunk timeout of
+// the caller, if there is any. The context is derived from the context of opts.
+func (caller *Caller) withChunkTimeout(opts *bind.CallOpts) (*bind.CallOpts, context.CancelFunc) {
+	if caller.chunkTimeout <= 0 {
+		return opts, func() {}
+	}
+	var chunkOpts bind.CallOpts
+	if opts != nil {
+		chunkOpts = *opts
+	}
+	parent, _ := caller.callContext(opts)
+	var cancel context.CancelFunc
+	chunkOpts.Context, cancel = context.WithTimeout(parent, caller.chunkTimeout)
+	return &chunkOpts, cancel
+}
@@ -0,0 +1,74 @@
+package multicall
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/jbexdp/go-multicall/contracts/contract_multicall"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_ChunkTimeout(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	var calls []*Call
+	for i := 0; i < 3; i++ {
+		calls = append(calls, testContract.NewCall(new(boolOutput), "testFunc", true))
+	}
+
+	var deadlines int
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		if _, ok := opts.Context.Deadline(); ok {
+			deadlines++
+		}
+	}
+	caller := &Caller{contract: stub, chunkTimeout: time.Second}
+
+	_, err = caller.CallChunkedContext(context.Background(), nil, 2, 0, calls...)
+	r.NoError(err)
+	r.Equal(2, deadlines)
+
+	// no timeout
+	deadlines = 0
+	caller.chunkTimeout = 0
+	_, err = caller.CallChunked(nil, 2, 0, calls...)
+	r.NoError(err)
+	r.Zero(deadlines)
+}
+
+func TestCaller_ChunkTimeoutRetry(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	// the first attempt hangs until the chunk times out
+	var (
+		attempts int
+		chunkErr error
+	)
+	stub := echoStub()
+	stub.checkOpts = func(opts *bind.CallOpts) {
+		attempts++
+		chunkErr = nil
+		if attempts == 1 {
+			<-opts.Context.Done()
+			chunkErr = opts.Context.Err()
+		}
+	}
+	stub.callErr = func(calls []contract_multicall.Multicall3Call3) error {
+		return chunkErr
+	}
+	caller := &Caller{contract: stub, chunkTimeout: 10 * time.Millisecond}
+
+	calls, err := caller.CallChunkedRetry(nil, 1, time.Millisecond, 1, testContract.NewCall(new(boolOutput), "testFunc", true))
+	r.NoError(err)
+	r.Equal(2, attempts)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+}
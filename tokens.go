@@ -0,0 +1,71 @@
+package multicall
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// erc20ABI is the view functions of the ERC20 ABI.
+const erc20ABI = `[
+	{"inputs": [], "name": "name", "outputs": [{"name": "", "type": "string"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "symbol", "outputs": [{"name": "", "type": "string"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "decimals", "outputs": [{"name": "", "type": "uint8"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "totalSupply", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "owner", "type": "address"}], "name": "balanceOf", "outputs": [{"name": "balance", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "owner", "type": "address"}, {"name": "spender", "type": "address"}], "name": "allowance", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"}
+]`
+
+// erc721ABI is the view functions of the ERC721 ABI with the metadata extension.
+const erc721ABI = `[
+	{"inputs": [{"name": "interfaceId", "type": "bytes4"}], "name": "supportsInterface", "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "name", "outputs": [{"name": "", "type": "string"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "symbol", "outputs": [{"name": "", "type": "string"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "tokenId", "type": "uint256"}], "name": "tokenURI", "outputs": [{"name": "", "type": "string"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "owner", "type": "address"}], "name": "balanceOf", "outputs": [{"name": "balance", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "tokenId", "type": "uint256"}], "name": "ownerOf", "outputs": [{"name": "owner", "type": "address"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "tokenId", "type": "uint256"}], "name": "getApproved", "outputs": [{"name": "operator", "type": "address"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "owner", "type": "address"}, {"name": "operator", "type": "address"}], "name": "isApprovedForAll", "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view", "type": "function"}
+]`
+
+// erc1155ABI is the view functions of the ERC1155 ABI with the metadata URI extension.
+const erc1155ABI = `[
+	{"inputs": [{"name": "interfaceId", "type": "bytes4"}], "name": "supportsInterface", "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "id", "type": "uint256"}], "name": "uri", "outputs": [{"name": "", "type": "string"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "account", "type": "address"}, {"name": "id", "type": "uint256"}], "name": "balanceOf", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "accounts", "type": "address[]"}, {"name": "ids", "type": "uint256[]"}], "name": "balanceOfBatch", "outputs": [{"name": "", "type": "uint256[]"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [{"name": "account", "type": "address"}, {"name": "operator", "type": "address"}], "name": "isApprovedForAll", "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view", "type": "function"}
+]`
+
+var (
+	erc20Parsed   = mustParseABI(erc20ABI)
+	erc721Parsed  = mustParseABI(erc721ABI)
+	erc1155Parsed = mustParseABI(erc1155ABI)
+)
+
+func mustParseABI(rawJson string) *abi.ABI {
+	parsedABI, err := ParseABI(rawJson)
+	if err != nil {
+		panic(err)
+	}
+	return parsedABI
+}
+
+// NewERC20 creates a call factory for the ERC20 token at the address with the view
+// functions of the ERC20 ABI: name, symbol, decimals, totalSupply, balanceOf and allowance.
+func NewERC20(addr common.Address) *Contract {
+	return &Contract{ABI: erc20Parsed, Address: addr}
+}
+
+// NewERC721 creates a call factory for the ERC721 token at the address with the view
+// functions of the ERC721 ABI and its metadata extension: supportsInterface, name, symbol,
+// tokenURI, balanceOf, ownerOf, getApproved and isApprovedForAll.
+func NewERC721(addr common.Address) *Contract {
+	return &Contract{ABI: erc721Parsed, Address: addr}
+}
+
+// NewERC1155 creates a call factory for the ERC1155 token at the address with the view
+// functions of the ERC1155 ABI and its metadata URI extension: supportsInterface, uri,
+// balanceOf, balanceOfBatch and isApprovedForAll.
+func NewERC1155(addr common.Address) *Contract {
+	return &Contract{ABI: erc1155Parsed, Address: addr}
+}
@@ -0,0 +1,62 @@
+package multicall
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenContracts(t *testing.T) {
+	r := require.New(t)
+
+	addr := common.HexToAddress(testAddr1)
+	testCases := []struct {
+		contract *Contract
+		methods  []string
+	}{
+		{NewERC20(addr), []string{"name", "symbol", "decimals", "totalSupply", "balanceOf", "allowance"}},
+		{NewERC721(addr), []string{"supportsInterface", "name", "symbol", "tokenURI", "balanceOf", "ownerOf", "getApproved", "isApprovedForAll"}},
+		{NewERC1155(addr), []string{"supportsInterface", "uri", "balanceOf", "balanceOfBatch", "isApprovedForAll"}},
+	}
+	for _, testCase := range testCases {
+		r.Equal(addr, testCase.contract.Address)
+		r.Len(testCase.contract.ABI.Methods, len(testCase.methods))
+		for _, method := range testCase.methods {
+			r.True(testCase.contract.ABI.Methods[method].IsConstant(), method)
+		}
+	}
+	r.Equal("0x95d89b41", selectorHex(NewERC20(addr).NewCall(nil, "symbol")))
+	r.Equal("0x6352211e", selectorHex(NewERC721(addr).NewCall(nil, "ownerOf", big.NewInt(1))))
+	r.Equal("0x00fdd58e", selectorHex(NewERC1155(addr).NewCall(nil, "balanceOf", addr, big.NewInt(1))))
+}
+
+func selectorHex(call *Call) string {
+	return hexutil.Encode(call.selector())
+}
+
+func TestCaller_TokenCalls(t *testing.T) {
+	r := require.New(t)
+
+	type ownerOutput struct {
+		Owner common.Address
+	}
+	type balanceOutput struct {
+		Balance *big.Int
+	}
+
+	holder := common.HexToAddress("0x0000000000000000000000000000000000000007")
+	caller := &Caller{contract: echoStub()}
+	// the stub returns the first argument as the output
+	calls, err := caller.Call(nil,
+		NewERC20(common.HexToAddress(testAddr1)).NewCall(new(balanceOutput), "allowance", holder, holder),
+		NewERC721(common.HexToAddress(testAddr2)).NewCall(new(ownerOutput), "ownerOf", big.NewInt(1)),
+		NewERC1155(common.HexToAddress(testAddr2)).NewCall(new(balanceOutput), "balanceOf", holder, big.NewInt(1)),
+	)
+	r.NoError(err)
+	r.Equal(big.NewInt(7), calls[0].Outputs.(*balanceOutput).Balance)
+	r.Equal(common.HexToAddress("0x0000000000000000000000000000000000000001"), calls[1].Outputs.(*ownerOutput).Owner)
+	r.Equal(big.NewInt(7), calls[2].Outputs.(*balanceOutput).Balance)
+}
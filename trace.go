@@ -0,0 +1,142 @@
+package multicall
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrTracingUnsupported is the error for profiling with a node which does not support
+// debug_traceCall.
+var ErrTracingUnsupported = errors.New("tracing is not supported by the node")
+
+// callTrace is the part of the callTracer result used for profiling.
+type callTrace struct {
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Calls   []callTrace    `json:"calls"`
+}
+
+// CallProfile is the gas used by a call in a multicall profiled with Caller.Profile.
+type CallProfile struct {
+	Index   int
+	GasUsed uint64
+}
+
+// CallTraced makes multicalls like Call and then traces each call separately with
+// debug_traceCall to set the gas used by the call as GasUsed. The calls are traced with
+// the multicall contract as msg.sender, and the gas used is the one reported by the
+// callTracer, which includes the intrinsic gas of the traced transaction. This needs a
+// caller created with Dial and a node which supports tracing, otherwise
+// ErrTracingUnsupported is returned. It is only meant for profiling since it makes a
+// request for each call.
+func (caller *Caller) CallTraced(opts *bind.CallOpts, calls ...*Call) ([]*Call, error) {
+	if caller.rpc == nil {
+		return calls, errors.New("caller has no rpc client")
+	}
+	calls, err := caller.Call(opts, calls...)
+	if err != nil && !errors.As(err, new(*MultiError)) {
+		return calls, err
+	}
+
+	ctx, _ := caller.callContext(opts)
+	for i, call := range calls {
+		data, packErr := call.Pack()
+		if packErr != nil {
+			continue
+		}
+		callArg := map[string]any{
+			"from": caller.address,
+			"to":   call.Contract.Address,
+			"data": hexutil.Bytes(data),
+		}
+		var trace callTrace
+		tracerConfig := map[string]any{"tracer": "callTracer"}
+		if err := caller.rpc.CallContext(ctx, &trace, "debug_traceCall", callArg, blockArg(opts), tracerConfig); err != nil {
+			if isMethodNotFound(err) {
+				return calls, fmt.Errorf("%w: %v", ErrTracingUnsupported, err)
+			}
+			return calls, fmt.Errorf("failed to trace call at index [%d]: %v", i, err)
+		}
+		call.GasUsed = uint64(trace.GasUsed)
+	}
+	return calls, err
+}
+
+// Profile traces the multicall of the calls with debug_traceCall and returns the gas used
+// by each call within the multicall, as reported by the callTracer for the calls made by
+// the multicall contract. The calls are not updated and the calls which fail to pack are
+// left out. ErrTracingUnsupported is returned when the node does not support tracing.
+// This needs a caller created with Dial.
+func (caller *Caller) Profile(opts *bind.CallOpts, calls ...*Call) ([]CallProfile, error) {
+	if caller.rpc == nil {
+		return nil, fmt.Errorf("%w: caller has no rpc client", ErrTracingUnsupported)
+	}
+	packedCalls, multiCalls, _, err := caller.pack(calls)
+	if err != nil {
+		return nil, err
+	}
+	multicall, err := caller.multicallContract()
+	if err != nil {
+		return nil, err
+	}
+	data, err := multicall.ABI.Pack("aggregate3", multiCalls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack multicall: %v", err)
+	}
+
+	ctx, _ := caller.callContext(opts)
+	callArg := map[string]any{
+		"to":   caller.address,
+		"data": hexutil.Bytes(data),
+	}
+	if opts != nil && opts.From != (common.Address{}) {
+		callArg["from"] = opts.From
+	}
+	var trace callTrace
+	tracerConfig := map[string]any{"tracer": "callTracer"}
+	if err := caller.rpc.CallContext(ctx, &trace, "debug_traceCall", callArg, blockArg(opts), tracerConfig); err != nil {
+		if isMethodNotFound(err) {
+			return nil, fmt.Errorf("%w: %v", ErrTracingUnsupported, err)
+		}
+		return nil, fmt.Errorf("failed to trace multicall: %v", err)
+	}
+	if len(trace.Calls) != len(packedCalls) {
+		return nil, fmt.Errorf("traced %d calls but the multicall has %d calls", len(trace.Calls), len(packedCalls))
+	}
+
+	indexes := callIndexes(calls, packedCalls)
+	profiles := make([]CallProfile, len(packedCalls))
+	for i, callTrace := range trace.Calls {
+		profiles[i] = CallProfile{Index: indexes[i], GasUsed: uint64(callTrace.GasUsed)}
+	}
+	return profiles, nil
+}
+
+// isMethodNotFound tells if the error is the JSON-RPC error of an unknown method.
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") || strings.Contains(msg, "does not exist") || strings.Contains(msg, "not available")
+}
+
+// blockArg returns the block parameter of the raw JSON-RPC requests for the call options.
+func blockArg(opts *bind.CallOpts) string {
+	switch {
+	case opts == nil:
+		return "latest"
+	case opts.BlockNumber != nil:
+		return hexutil.EncodeBig(opts.BlockNumber)
+	case opts.Pending:
+		return "pending"
+	default:
+		return "latest"
+	}
+}
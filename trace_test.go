@@ -0,0 +1,143 @@
+package multicall
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+type traceArgs struct {
+	From common.Address `json:"from"`
+	To   common.Address `json:"to"`
+	Data hexutil.Bytes  `json:"data"`
+}
+
+type traceConfig struct {
+	Tracer string `json:"tracer"`
+}
+
+type debugService struct {
+	blocks   []string
+	subCalls int
+	err      error
+}
+
+// TraceCall reports the length of the calldata as the gas used. The trace of a multicall
+// has the sub calls which use 100 gas each.
+func (service *debugService) TraceCall(args traceArgs, block string, config traceConfig) (map[string]any, error) {
+	if service.err != nil {
+		return nil, service.err
+	}
+	if config.Tracer != "callTracer" {
+		return nil, errors.New("unexpected tracer")
+	}
+	service.blocks = append(service.blocks, block)
+	if args.To == common.HexToAddress(DefaultAddress) {
+		calls := make([]map[string]any, service.subCalls)
+		for i := range calls {
+			calls[i] = map[string]any{"gasUsed": hexutil.Uint64(100 * (i + 1))}
+		}
+		return map[string]any{"gasUsed": hexutil.Uint64(50000), "calls": calls}, nil
+	}
+	if args.From != common.HexToAddress(DefaultAddress) {
+		return nil, errors.New("unexpected sender")
+	}
+	return map[string]any{"gasUsed": hexutil.Uint64(21000 + len(args.Data))}, nil
+}
+
+func TestCaller_CallTraced(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	service := &debugService{}
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("debug", service))
+	defer server.Stop()
+
+	caller := &Caller{
+		contract: echoStub(),
+		rpc:      rpc.DialInProc(server),
+		address:  common.HexToAddress(DefaultAddress),
+	}
+
+	calls := []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+	}
+	calls, err = caller.CallTraced(&bind.CallOpts{BlockNumber: big.NewInt(testBlockNumber)}, calls...)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.True(calls[0].Outputs.(*boolOutput).Val1)
+	r.Equal(uint64(21000+36), calls[0].GasUsed)
+	r.Zero(calls[1].GasUsed)
+	r.Equal([]string{hexutil.EncodeBig(big.NewInt(testBlockNumber))}, service.blocks)
+
+	service.err = errors.New("internal error")
+	_, err = caller.CallTraced(nil, calls[0])
+	r.ErrorContains(err, "failed to trace call at index [0]")
+
+	service.err = errors.New("the method debug_traceCall does not exist/is not available")
+	_, err = caller.CallTraced(nil, calls[0])
+	r.ErrorIs(err, ErrTracingUnsupported)
+
+	_, err = (&Caller{contract: echoStub()}).CallTraced(nil, calls[0])
+	r.ErrorContains(err, "no rpc client")
+}
+
+func TestCaller_Profile(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	service := &debugService{subCalls: 2}
+	server := rpc.NewServer()
+	r.NoError(server.RegisterName("debug", service))
+	defer server.Stop()
+
+	caller := &Caller{
+		contract: echoStub(),
+		rpc:      rpc.DialInProc(server),
+		address:  common.HexToAddress(DefaultAddress),
+	}
+
+	calls := []*Call{
+		testContract.NewCall(new(boolOutput), "testFunc", true),
+		testContract.NewCall(new(boolOutput), "testFunc", 'a'), // bad input
+		testContract.NewCall(new(boolOutput), "testFunc", false),
+	}
+	profiles, err := caller.Profile(nil, calls...)
+	r.NoError(err)
+	r.Equal([]CallProfile{{Index: 0, GasUsed: 100}, {Index: 2, GasUsed: 200}}, profiles)
+	r.False(calls[0].Outputs.(*boolOutput).Val1)
+
+	service.subCalls = 1
+	_, err = caller.Profile(nil, calls...)
+	r.ErrorContains(err, "traced 1 calls")
+
+	server.Stop()
+	unsupported := rpc.NewServer()
+	defer unsupported.Stop()
+	caller.rpc = rpc.DialInProc(unsupported)
+	_, err = caller.Profile(nil, calls...)
+	r.ErrorIs(err, ErrTracingUnsupported)
+
+	_, err = (&Caller{contract: echoStub()}).Profile(nil, calls...)
+	r.ErrorIs(err, ErrTracingUnsupported)
+}
+
+func TestBlockArg(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("latest", blockArg(nil))
+	r.Equal("pending", blockArg(&bind.CallOpts{Pending: true}))
+	r.Equal("0x1e240", blockArg(&bind.CallOpts{BlockNumber: big.NewInt(testBlockNumber)}))
+}
@@ -0,0 +1,30 @@
+package multicall
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ValidateCalls checks the calls before they are made and returns an error for the first
+// call which is nil, has no contract or targets the zero address.
+func ValidateCalls(calls ...*Call) error {
+	for i, call := range calls {
+		switch {
+		case call == nil:
+			return fmt.Errorf("call at index [%d] is nil", i)
+		case call.Contract == nil:
+			return fmt.Errorf("call at index [%d] has no contract", i)
+		case call.Contract.Address == (common.Address{}):
+			return fmt.Errorf("call at index [%d] targets the zero address", i)
+		}
+	}
+	return nil
+}
+
+// PackAll packs the calls without making them. The returned error is a *MultiError with
+// the calls which fail to pack.
+func PackAll(calls ...*Call) error {
+	_, _, multiErr := packCallsLenient(calls)
+	return multiErr.errOrNil()
+}
@@ -0,0 +1,46 @@
+package multicall
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCalls(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+	zeroContract, err := NewContract(oneValueABI, common.Address{}.Hex())
+	r.NoError(err)
+
+	call := testContract.NewCall(new(boolOutput), "testFunc", true)
+	r.NoError(ValidateCalls(call, call))
+
+	r.EqualError(ValidateCalls(call, nil), "call at index [1] is nil")
+	r.EqualError(ValidateCalls(call, &Call{Method: "testFunc"}), "call at index [1] has no contract")
+	r.EqualError(ValidateCalls(zeroContract.NewCall(new(boolOutput), "testFunc", true)), "call at index [0] targets the zero address")
+}
+
+func TestPackAll(t *testing.T) {
+	r := require.New(t)
+
+	testContract, err := NewContract(oneValueABI, testAddr1)
+	r.NoError(err)
+
+	call := testContract.NewCall(new(boolOutput), "testFunc", true)
+	r.NoError(PackAll(call, call))
+
+	err = PackAll(
+		call,
+		testContract.NewCall(new(boolOutput), "testFunc", "not a bool"),
+		testContract.NewCall(new(boolOutput), "unknownFunc"),
+	)
+	var multiErr *MultiError
+	r.ErrorAs(err, &multiErr)
+	r.Len(multiErr.Errors, 2)
+	r.Equal(1, multiErr.Errors[0].Index)
+	r.Equal(2, multiErr.Errors[1].Index)
+	r.ErrorContains(multiErr.Errors[0], "failed to pack call inputs")
+}